@@ -4,10 +4,13 @@ go 1.23.0
 
 toolchain go1.23.11
 
+require (
+	github.com/spf13/cobra v1.9.1
+	golang.org/x/mod v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.9.1 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
-	golang.org/x/mod v0.26.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -14,6 +14,7 @@ import (
 // Generator handles changelog generation
 type Generator struct {
 	config *config.Config
+	links  *Links
 }
 
 // Release represents a release entry in the changelog
@@ -23,12 +24,18 @@ type Release struct {
 	Commits     []*commits.ConventionalCommit
 	GroupedCommits map[string][]*commits.ConventionalCommit
 	BreakingChanges []*commits.ConventionalCommit
+	PreviousTag     string // Tag of the previous release, used for the compare link
+	Contributors    []*Contributor
+	Reverted        []*commits.RevertPair
+	Issues          []*Issue // Issues referenced by the release, including hidden commit types
+	Notes           []string // Extra markdown paragraphs, e.g. from plugins, shown before the changes
 }
 
 // NewGenerator creates a new changelog generator
 func NewGenerator(cfg *config.Config) *Generator {
 	return &Generator{
 		config: cfg,
+		links:  NewLinks(cfg.Repository),
 	}
 }
 
@@ -36,22 +43,38 @@ func NewGenerator(cfg *config.Config) *Generator {
 func (g *Generator) GenerateRelease(ver *version.Version, conventionalCommits []*commits.ConventionalCommit) *Release {
 	parser := commits.NewParser(g.config)
 	
+	// Changes reverted within the release cancel out
+	activeCommits, reverted := parser.CancelReverts(conventionalCommits)
+
 	// Filter commits for changelog
-	filteredCommits := parser.FilterCommitsForChangelog(conventionalCommits)
+	filteredCommits := parser.FilterCommitsForChangelog(activeCommits)
 	
 	// Group commits by type
 	groupedCommits := parser.GroupCommitsByType(filteredCommits)
 	
 	// Get breaking changes
-	breakingChanges := parser.GetBreakingChanges(conventionalCommits)
+	breakingChanges := parser.GetBreakingChanges(activeCommits)
 
-	return &Release{
+	release := &Release{
 		Version:         ver,
 		Date:            time.Now(),
 		Commits:         filteredCommits,
 		GroupedCommits:  groupedCommits,
 		BreakingChanges: breakingChanges,
 	}
+
+	if g.config.Commits.Reverts.List {
+		release.Reverted = reverted
+	}
+
+	release.Issues = g.collectIssues(activeCommits)
+
+	// Contributors cover every commit in the range, not only the listed ones
+	if g.config.Changelog.Contributors.Enabled {
+		release.Contributors = g.collectContributors(conventionalCommits)
+	}
+
+	return release
 }
 
 // FormatRelease formats a release entry as markdown
@@ -59,17 +82,25 @@ func (g *Generator) FormatRelease(release *Release) string {
 	var builder strings.Builder
 	parser := commits.NewParser(g.config)
 
-	// Release header
+	// Release header, linking to the comparison with the previous release
 	builder.WriteString(fmt.Sprintf("## [%s]", release.Version.String()))
+	currentTag := version.NewManager(g.config).FormatTagName(release.Version)
+	if compareURL := g.links.Compare(release.PreviousTag, currentTag); compareURL != "" {
+		builder.WriteString(fmt.Sprintf("(%s)", compareURL))
+	}
 	builder.WriteString(fmt.Sprintf(" - %s\n\n", release.Date.Format("2006-01-02")))
 
+	for _, note := range release.Notes {
+		builder.WriteString(strings.TrimSpace(note) + "\n\n")
+	}
+
 	// Breaking changes section (if any)
 	if len(release.BreakingChanges) > 0 {
-		builder.WriteString("### ⚠ BREAKING CHANGES\n\n")
+		builder.WriteString("### " + breakingGroupTitle + "\n\n")
 		for _, commit := range release.BreakingChanges {
-			builder.WriteString(fmt.Sprintf("* %s", commit.Description))
+			builder.WriteString(fmt.Sprintf("* %s", g.links.LinkIssues(commit.Description)))
 			if commit.Scope != "" {
-				builder.WriteString(fmt.Sprintf(" (**%s**)", commit.Scope))
+				builder.WriteString(fmt.Sprintf(" (**%s**)", parser.GetScopeTitle(commit.Scope)))
 			}
 			builder.WriteString("\n")
 			
@@ -83,45 +114,135 @@ func (g *Generator) FormatRelease(release *Release) string {
 		builder.WriteString("\n")
 	}
 
+	switch strings.ToLower(g.config.Changelog.GroupBy) {
+	case "scope":
+		g.formatScopeSections(&builder, parser, release.Commits, "###")
+	case "type-then-scope":
+		g.formatTypeSections(&builder, parser, release.GroupedCommits, true)
+	default:
+		g.formatTypeSections(&builder, parser, release.GroupedCommits, false)
+	}
+
+	// Changes that were made and reverted within this release
+	if len(release.Reverted) > 0 {
+		builder.WriteString("### " + revertedGroupTitle + "\n\n")
+		for _, pair := range release.Reverted {
+			builder.WriteString(fmt.Sprintf("* %s%s, reverted by%s\n",
+				pair.Original.Original.Subject,
+				g.formatCommitHash(pair.Original.Original.Hash),
+				g.formatCommitHash(pair.Revert.Original.Hash)))
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(g.formatContributors(release.Contributors))
+
+	return builder.String()
+}
+
+// formatTypeSections writes one section per commit type, optionally nesting a section per scope
+func (g *Generator) formatTypeSections(builder *strings.Builder, parser *commits.Parser, groupedCommits map[string][]*commits.ConventionalCommit, nestScopes bool) {
 	// Sort commit types for consistent ordering
-	sortedTypes := parser.SortCommitsByType(release.GroupedCommits)
+	sortedTypes := parser.SortCommitsByType(groupedCommits)
 
 	// Generate sections for each commit type
 	for _, commitType := range sortedTypes {
-		commits := release.GroupedCommits[commitType]
-		if len(commits) == 0 {
+		typeCommits := groupedCommits[commitType]
+		if len(typeCommits) == 0 {
 			continue
 		}
 
 		// Section header
-		typeTitle := parser.GetCommitTypeTitle(commitType)
+		typeTitle := parser.GetCommitTypeHeading(commitType)
 		builder.WriteString(fmt.Sprintf("### %s\n\n", typeTitle))
 
+		if nestScopes {
+			g.formatScopeSections(builder, parser, typeCommits, "####")
+			continue
+		}
+
 		// List commits
-		for _, commit := range commits {
-			builder.WriteString("* ")
-			
-			// Add scope if present
-			if commit.Scope != "" {
-				builder.WriteString(fmt.Sprintf("**%s:** ", commit.Scope))
-			}
-			
-			builder.WriteString(commit.Description)
-			
-			// Add commit hash (short)
-			if len(commit.Original.Hash) >= 7 {
-				shortHash := commit.Original.Hash[:7]
-				builder.WriteString(fmt.Sprintf(" ([%s])", shortHash))
-			}
-			
-			builder.WriteString("\n")
+		for _, commit := range typeCommits {
+			builder.WriteString(g.formatEntry(parser, commit, true))
 		}
 		builder.WriteString("\n")
 	}
+}
 
+// formatScopeSections writes one section per scope, unscoped commits last under "General"
+func (g *Generator) formatScopeSections(builder *strings.Builder, parser *commits.Parser, scopeCommits []*commits.ConventionalCommit, heading string) {
+	groups := parser.GroupCommitsByScope(scopeCommits)
+
+	for _, scope := range parser.SortScopes(groups) {
+		title := parser.GetScopeTitle(scope)
+		if scope == "" {
+			title = "General"
+		}
+		builder.WriteString(fmt.Sprintf("%s %s\n\n", heading, title))
+
+		for _, commit := range groups[scope] {
+			builder.WriteString(g.formatEntry(parser, commit, false))
+		}
+		builder.WriteString("\n")
+	}
+}
+
+// formatEntry formats a single commit as a changelog list item
+func (g *Generator) formatEntry(parser *commits.Parser, commit *commits.ConventionalCommit, showScope bool) string {
+	var builder strings.Builder
+	builder.WriteString("* ")
+
+	// Add scope if present
+	if showScope && commit.Scope != "" {
+		builder.WriteString(fmt.Sprintf("**%s:** ", parser.GetScopeTitle(commit.Scope)))
+	}
+
+	builder.WriteString(g.links.LinkIssues(commit.Description))
+	builder.WriteString(g.formatReferences(commit.References))
+	builder.WriteString(g.formatCommitHash(commit.Original.Hash))
+
+	builder.WriteString("\n")
 	return builder.String()
 }
 
+// formatCommitHash formats the short hash of a commit, linked when the repository URL is known
+func (g *Generator) formatCommitHash(hash string) string {
+	if len(hash) < 7 {
+		return ""
+	}
+	shortHash := hash[:7]
+	if commitURL := g.links.Commit(hash); commitURL != "" {
+		return fmt.Sprintf(" ([%s](%s))", shortHash, commitURL)
+	}
+	return fmt.Sprintf(" (%s)", shortHash)
+}
+
+// formatReferences formats the issue references from a commit's footers, grouped
+// by footer action, e.g. " (closes [#12](...); refs PROJ-3)". References
+// mentioned in the description are already linked there.
+func (g *Generator) formatReferences(references []commits.Reference) string {
+	var actions []string
+	grouped := make(map[string][]string)
+	for _, reference := range references {
+		if reference.Action == commits.ActionMentions {
+			continue
+		}
+		if _, exists := grouped[reference.Action]; !exists {
+			actions = append(actions, reference.Action)
+		}
+		grouped[reference.Action] = append(grouped[reference.Action], g.links.Reference(reference))
+	}
+
+	if len(actions) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, action := range actions {
+		parts = append(parts, action+" "+strings.Join(grouped[action], ", "))
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(parts, "; "))
+}
+
 // ReadExistingChangelog reads the existing changelog file
 func (g *Generator) ReadExistingChangelog() (string, error) {
 	content, err := os.ReadFile(g.config.Changelog.File)
@@ -143,82 +264,43 @@ func (g *Generator) WriteChangelog(content string) error {
 	return nil
 }
 
-// PrependRelease adds a new release to the beginning of the changelog
-func (g *Generator) PrependRelease(release *Release) error {
+// UpsertRelease writes a release to the changelog. An existing section for the
+// same version is replaced in place, otherwise the release is added at the top.
+func (g *Generator) UpsertRelease(release *Release) error {
 	// Read existing changelog
 	existingContent, err := g.ReadExistingChangelog()
 	if err != nil {
 		return err
 	}
 
-	// Format the new release
-	newRelease := g.FormatRelease(release)
-
-	// Create the new changelog content
-	var newContent strings.Builder
-
-	// Add changelog header if it doesn't exist
-	if existingContent == "" || !strings.Contains(existingContent, "# Changelog") {
-		newContent.WriteString("# Changelog\n\n")
-		newContent.WriteString("All notable changes to this project will be documented in this file.\n\n")
-		newContent.WriteString("The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),\n")
-		newContent.WriteString("and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).\n\n")
-	}
-
-	// Add the new release
-	newContent.WriteString(newRelease)
-
-	// Add existing content (but skip the header if we just added it)
-	if existingContent != "" {
-		if strings.Contains(existingContent, "# Changelog") {
-			// Find the end of the header section
-			lines := strings.Split(existingContent, "\n")
-			var contentStartIndex int
-			headerEndFound := false
-			
-			for i, line := range lines {
-				if headerEndFound && strings.TrimSpace(line) != "" {
-					contentStartIndex = i
-					break
-				}
-				if strings.HasPrefix(line, "## ") {
-					contentStartIndex = i
-					break
-				}
-				if strings.Contains(line, "Semantic Versioning") {
-					headerEndFound = true
-				}
-			}
-			
-			if contentStartIndex > 0 {
-				remainingContent := strings.Join(lines[contentStartIndex:], "\n")
-				newContent.WriteString(remainingContent)
-			}
-		} else {
-			newContent.WriteString(existingContent)
-		}
-	}
+	doc := ParseDocument(existingContent)
+	doc.SetRelease(release.Version.String(), g.FormatRelease(release), g.config.Changelog.Unreleased)
 
 	// Write the new changelog
-	return g.WriteChangelog(newContent.String())
+	return g.WriteChangelog(doc.String())
 }
 
 // GenerateFullChangelog generates a complete changelog from scratch
 func (g *Generator) GenerateFullChangelog(releases []*Release) error {
+	return g.WriteChangelog(g.FormatFullChangelog(releases))
+}
+
+// FormatFullChangelog formats a complete changelog, releases are written in the given order
+func (g *Generator) FormatFullChangelog(releases []*Release) string {
 	var content strings.Builder
 
 	// Header
-	content.WriteString("# Changelog\n\n")
-	content.WriteString("All notable changes to this project will be documented in this file.\n\n")
-	content.WriteString("The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),\n")
-	content.WriteString("and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).\n\n")
+	content.WriteString(defaultHeader)
+	if g.config.Changelog.Unreleased {
+		content.WriteString("## [" + unreleasedVersion + "]\n\n")
+	}
 
 	// Add each release
 	for _, release := range releases {
 		content.WriteString(g.FormatRelease(release))
 	}
 
-	return g.WriteChangelog(content.String())
+	return content.String()
 }
 
 // ValidateChangelogPath checks if the changelog path is valid
@@ -0,0 +1,123 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+
+	"herald/internal/commits"
+)
+
+// coAuthorPattern matches "Co-authored-by: Name <email>" trailers
+var coAuthorPattern = regexp.MustCompile(`(?mi)^co-authored-by:\s*(.+?)\s*<([^>]+)>\s*$`)
+
+// Contributor is an author or co-author of commits in a release
+type Contributor struct {
+	Name      string
+	Email     string
+	Handle    string
+	Commits   int
+	FirstTime bool
+}
+
+// collectContributors returns the unique authors and co-authors of the commits, in order of appearance
+func (g *Generator) collectContributors(conventionalCommits []*commits.ConventionalCommit) []*Contributor {
+	var contributors []*Contributor
+	byEmail := make(map[string]*Contributor)
+
+	add := func(name, email string) {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if key == "" || g.isBot(name, email) {
+			return
+		}
+		if contributor, exists := byEmail[key]; exists {
+			contributor.Commits++
+			return
+		}
+		contributor := &Contributor{
+			Name:    strings.TrimSpace(name),
+			Email:   key,
+			Handle:  g.contributorHandle(key),
+			Commits: 1,
+		}
+		byEmail[key] = contributor
+		contributors = append(contributors, contributor)
+	}
+
+	for _, commit := range conventionalCommits {
+		if commit.Original == nil {
+			continue
+		}
+		add(commit.Original.Author, commit.Original.Email)
+		for _, match := range coAuthorPattern.FindAllStringSubmatch(commit.Original.Body, -1) {
+			add(match[1], match[2])
+		}
+	}
+
+	return contributors
+}
+
+// contributorHandle looks up the configured handle for an email address
+func (g *Generator) contributorHandle(email string) string {
+	for mappedEmail, handle := range g.config.Changelog.Contributors.Handles {
+		if strings.EqualFold(mappedEmail, email) {
+			return strings.TrimPrefix(handle, "@")
+		}
+	}
+	return ""
+}
+
+// isBot reports whether an author should be filtered out as a bot
+func (g *Generator) isBot(name, email string) bool {
+	settings := g.config.Changelog.Contributors
+	if !settings.ExcludeBots {
+		return false
+	}
+
+	name = strings.ToLower(name)
+	email = strings.ToLower(email)
+	if strings.HasSuffix(name, "[bot]") || strings.Contains(email, "[bot]") {
+		return true
+	}
+	for _, bot := range settings.Bots {
+		bot = strings.ToLower(bot)
+		if bot != "" && (strings.Contains(name, bot) || strings.Contains(email, bot)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkFirstTimeContributors flags contributors whose email is not among the known earlier authors
+func (g *Generator) MarkFirstTimeContributors(release *Release, knownEmails map[string]bool) {
+	if !g.config.Changelog.Contributors.MarkFirstTime {
+		return
+	}
+	for _, contributor := range release.Contributors {
+		contributor.FirstTime = !knownEmails[contributor.Email]
+	}
+}
+
+// formatContributors formats the contributors section of a release
+func (g *Generator) formatContributors(contributors []*Contributor) string {
+	if len(contributors) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString("### " + contributorsGroupTitle + "\n\n")
+	for _, contributor := range contributors {
+		builder.WriteString("* ")
+		if contributor.Handle != "" {
+			builder.WriteString("@" + contributor.Handle)
+		} else {
+			builder.WriteString(contributor.Name)
+		}
+		if contributor.FirstTime {
+			builder.WriteString(" (first contribution)")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+
+	return builder.String()
+}
@@ -0,0 +1,63 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/git"
+	"herald/internal/version"
+)
+
+func TestContributorsRegeneratedWithChangedHandles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Changelog.File = filepath.Join(t.TempDir(), "CHANGELOG.md")
+	cfg.Changelog.Contributors.Enabled = true
+
+	ver, err := version.NewManager(cfg).ParseVersion("v1.0.0")
+	if err != nil {
+		t.Fatalf("ParseVersion() error = %v", err)
+	}
+	parser := commits.NewParser(cfg)
+	conventionalCommits, err := parser.ParseCommits([]*git.Commit{
+		{Hash: "abc1234", Subject: "feat: add export", Author: "B", Email: "bee@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("ParseCommits() error = %v", err)
+	}
+
+	release := func() {
+		t.Helper()
+		generator := NewGenerator(cfg)
+		rel := generator.GenerateRelease(ver, conventionalCommits)
+		rel.Date = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		generator.MarkFirstTimeContributors(rel, map[string]bool{})
+		if err := generator.UpsertRelease(rel); err != nil {
+			t.Fatalf("UpsertRelease() error = %v", err)
+		}
+	}
+
+	cfg.Changelog.Contributors.Handles = map[string]string{"bee@example.com": "bee"}
+	release()
+	cfg.Changelog.Contributors.Handles = nil
+	release()
+	release()
+
+	content, err := os.ReadFile(cfg.Changelog.File)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(content), "@bee") {
+		t.Errorf("changelog still lists the old handle:\n%s", content)
+	}
+	if got := strings.Count(string(content), "* B (first contribution)"); got != 1 {
+		t.Errorf("contributor listed %d times, want once:\n%s", got, content)
+	}
+	if got := strings.Count(string(content), "### Contributors"); got != 1 {
+		t.Errorf("%d Contributors groups, want one:\n%s", got, content)
+	}
+}
@@ -0,0 +1,100 @@
+package changelog
+
+import (
+	"strings"
+)
+
+// defaultHeader is written at the top of every changelog created by herald
+const defaultHeader = "# Changelog\n\n" +
+	"All notable changes to this project will be documented in this file.\n\n" +
+	"The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),\n" +
+	"and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).\n\n"
+
+// unreleasedVersion is the section name used for changes not yet released
+const unreleasedVersion = "Unreleased"
+
+// Document is an existing changelog split into its header and release sections
+type Document struct {
+	Header     string
+	Unreleased *Section
+	Sections   []*Section
+}
+
+// normalizeVersion makes "v1.2.0" and "1.2.0" compare equal
+func normalizeVersion(ver string) string {
+	return strings.TrimPrefix(strings.TrimSpace(ver), "v")
+}
+
+// FindSection returns the index of the section for the given version, or -1
+func (d *Document) FindSection(ver string) int {
+	for i, section := range d.Sections {
+		if normalizeVersion(section.Version) == normalizeVersion(ver) {
+			return i
+		}
+	}
+	return -1
+}
+
+// UnreleasedNotes returns the hand-written content of the Unreleased section without its heading
+func (d *Document) UnreleasedNotes() string {
+	if d.Unreleased == nil {
+		return ""
+	}
+	return d.Unreleased.Body()
+}
+
+// SetRelease replaces the section for the release version in place, or adds it at the top.
+// Notes collected under Unreleased are moved into the release section.
+func (d *Document) SetRelease(ver string, content string, keepUnreleased bool) {
+	section := parseSection(content)
+	section.Version = ver
+	if d.UnreleasedNotes() != "" {
+		section.mergeUnreleased(d.Unreleased)
+		section.Content = section.Markdown()
+	}
+	d.Unreleased = nil
+	if idx := d.FindSection(ver); idx >= 0 {
+		// Keep entries that were added to the existing section by hand
+		if section.mergeHandWritten(d.Sections[idx]) {
+			section.Content = section.Markdown()
+		}
+		d.Sections[idx] = section
+	} else {
+		d.Sections = append([]*Section{section}, d.Sections...)
+	}
+
+	if keepUnreleased {
+		d.Unreleased = &Section{
+			Version: unreleasedVersion,
+			Content: "## [" + unreleasedVersion + "]\n",
+		}
+	}
+}
+
+// String renders the document back to markdown
+func (d *Document) String() string {
+	var builder strings.Builder
+
+	header := d.Header
+	if !strings.Contains(header, "# Changelog") {
+		header = defaultHeader + header
+	}
+	builder.WriteString(strings.TrimRight(header, "\n") + "\n\n")
+
+	var sections []*Section
+	if d.Unreleased != nil {
+		sections = append(sections, d.Unreleased)
+	}
+	sections = append(sections, d.Sections...)
+
+	for i, section := range sections {
+		builder.WriteString(strings.TrimRight(section.Content, "\n"))
+		if i < len(sections)-1 {
+			builder.WriteString("\n\n")
+		} else {
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,68 @@
+package changelog
+
+import (
+	"slices"
+
+	"herald/internal/commits"
+)
+
+// Issue is an issue referenced by the commits of a release
+type Issue struct {
+	Tracker  string   `json:"tracker,omitempty"`
+	ID       string   `json:"id"`
+	Text     string   `json:"text"`
+	URL      string   `json:"url,omitempty"`
+	Actions  []string `json:"actions"`  // Footer tokens and "mentions", e.g. ["closes"]
+	Resolved bool     `json:"resolved"` // A commit closes, fixes or resolves the issue
+	Commits  []string `json:"commits"`  // Hashes of the referencing commits
+}
+
+// resolvingActions are the footer tokens that mark an issue as resolved
+var resolvingActions = map[string]bool{
+	"closes":   true,
+	"close":    true,
+	"fixes":    true,
+	"fix":      true,
+	"resolves": true,
+	"resolve":  true,
+}
+
+// collectIssues gathers the issues referenced by the commits, in order of first reference
+func (g *Generator) collectIssues(conventionalCommits []*commits.ConventionalCommit) []*Issue {
+	var issues []*Issue
+	byKey := make(map[string]*Issue)
+
+	for _, commit := range conventionalCommits {
+		for _, reference := range commit.References {
+			// Values no tracker recognizes, such as commit hashes, are not issues
+			if reference.Tracker == "" {
+				continue
+			}
+
+			key := reference.Tracker + "\x00" + reference.ID
+			issue, exists := byKey[key]
+			if !exists {
+				issue = &Issue{
+					Tracker: reference.Tracker,
+					ID:      reference.ID,
+					Text:    reference.Text,
+					URL:     g.links.ReferenceURL(reference),
+				}
+				byKey[key] = issue
+				issues = append(issues, issue)
+			}
+
+			if !slices.Contains(issue.Actions, reference.Action) {
+				issue.Actions = append(issue.Actions, reference.Action)
+			}
+			if resolvingActions[reference.Action] {
+				issue.Resolved = true
+			}
+			if commit.Original != nil && !slices.Contains(issue.Commits, commit.Original.Hash) {
+				issue.Commits = append(issue.Commits, commit.Original.Hash)
+			}
+		}
+	}
+
+	return issues
+}
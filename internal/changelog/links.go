@@ -0,0 +1,240 @@
+package changelog
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"herald/internal/commits"
+	"herald/internal/config"
+)
+
+// providerTemplates holds the default link templates per hosting provider
+var providerTemplates = map[string]config.RepositoryConfig{
+	"github": {
+		CommitURL:  "{url}/commit/{hash}",
+		CompareURL: "{url}/compare/{previous}...{current}",
+		IssueURL:   "{url}/issues/{id}",
+	},
+	"gitlab": {
+		CommitURL:  "{url}/-/commit/{hash}",
+		CompareURL: "{url}/-/compare/{previous}...{current}",
+		IssueURL:   "{url}/-/issues/{id}",
+	},
+	"gitea": {
+		CommitURL:  "{url}/commit/{hash}",
+		CompareURL: "{url}/compare/{previous}...{current}",
+		IssueURL:   "{url}/issues/{id}",
+	},
+	"bitbucket": {
+		CommitURL:  "{url}/commits/{hash}",
+		CompareURL: "{url}/branches/compare/{current}%0D{previous}",
+		IssueURL:   "{url}/issues/{id}",
+	},
+}
+
+// Links renders URLs to commits, version comparisons and issues
+type Links struct {
+	commitURL  string
+	compareURL string
+	issueURL   string
+	trackers   []*linkTracker
+}
+
+// linkTracker is an issue tracker with its resolved URL template
+type linkTracker struct {
+	name  string
+	regex *regexp.Regexp
+	url   string // Template with {id}, empty when references are not linked
+}
+
+// NewLinks creates link templates from the repository configuration.
+// Templates that cannot be resolved are left empty and produce no links.
+func NewLinks(cfg config.RepositoryConfig) *Links {
+	repoURL := NormalizeRemoteURL(cfg.URL)
+
+	provider := strings.ToLower(cfg.Provider)
+	if provider == "" {
+		provider = DetectProvider(repoURL)
+	}
+	defaults := providerTemplates[provider]
+
+	resolve := func(template, fallback string) string {
+		if template == "" {
+			template = fallback
+		}
+		if template == "" || (strings.Contains(template, "{url}") && repoURL == "") {
+			return ""
+		}
+		return strings.ReplaceAll(template, "{url}", repoURL)
+	}
+
+	links := &Links{
+		commitURL:  resolve(cfg.CommitURL, defaults.CommitURL),
+		compareURL: resolve(cfg.CompareURL, defaults.CompareURL),
+		issueURL:   resolve(cfg.IssueURL, defaults.IssueURL),
+	}
+
+	for _, trackerConfig := range cfg.IssueTrackers() {
+		regex, err := trackerConfig.Regexp()
+		if err != nil {
+			continue
+		}
+		trackerURL := resolve(trackerConfig.URL, "")
+		if trackerConfig.URL == "" {
+			trackerURL = links.issueURL
+		}
+		links.trackers = append(links.trackers, &linkTracker{name: trackerConfig.Name, regex: regex, url: trackerURL})
+	}
+
+	return links
+}
+
+// NormalizeRemoteURL converts a git remote (SSH or HTTPS) into the repository web URL
+func NormalizeRemoteURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	// scp-like syntax: git@github.com:owner/repo.git
+	if !strings.Contains(remote, "://") {
+		if at := strings.Index(remote, "@"); at >= 0 {
+			remote = remote[at+1:]
+		}
+		remote = "https://" + strings.Replace(remote, ":", "/", 1)
+	}
+
+	parsed, err := url.Parse(remote)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	scheme := parsed.Scheme
+	if scheme != "http" {
+		scheme = "https"
+	}
+
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	return fmt.Sprintf("%s://%s/%s", scheme, parsed.Hostname(), path)
+}
+
+// DetectProvider guesses the hosting provider from the repository URL
+func DetectProvider(repoURL string) string {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case strings.Contains(host, "github"):
+		return "github"
+	case strings.Contains(host, "gitlab"):
+		return "gitlab"
+	case strings.Contains(host, "bitbucket"):
+		return "bitbucket"
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"), strings.Contains(host, "codeberg"):
+		return "gitea"
+	default:
+		return ""
+	}
+}
+
+// Commit returns the URL for a commit, or an empty string
+func (l *Links) Commit(hash string) string {
+	if l.commitURL == "" {
+		return ""
+	}
+	shortHash := hash
+	if len(shortHash) > 7 {
+		shortHash = shortHash[:7]
+	}
+	link := strings.ReplaceAll(l.commitURL, "{short_hash}", shortHash)
+	return strings.ReplaceAll(link, "{hash}", hash)
+}
+
+// Compare returns the URL comparing two tags, or an empty string
+func (l *Links) Compare(previous, current string) string {
+	if l.compareURL == "" || previous == "" {
+		return ""
+	}
+	link := strings.ReplaceAll(l.compareURL, "{previous}", previous)
+	return strings.ReplaceAll(link, "{current}", current)
+}
+
+// Issue returns the URL for an issue or pull request number, or an empty string
+func (l *Links) Issue(id string) string {
+	if l.issueURL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(l.issueURL, "{id}", strings.TrimPrefix(id, "#"))
+}
+
+// ReferenceURL returns the URL of an issue reference, or an empty string
+func (l *Links) ReferenceURL(reference commits.Reference) string {
+	for _, tracker := range l.trackers {
+		if tracker.name == reference.Tracker && tracker.url != "" {
+			return strings.ReplaceAll(tracker.url, "{id}", reference.ID)
+		}
+	}
+	return ""
+}
+
+// LinkIssues turns the issue references of every tracker in text into markdown
+// links, leaving existing links alone
+func (l *Links) LinkIssues(text string) string {
+	for _, tracker := range l.trackers {
+		if tracker.url == "" {
+			continue
+		}
+		text = replaceOutsideLinks(text, func(segment string) string {
+			return linkTrackerMatches(tracker, segment)
+		})
+	}
+	return text
+}
+
+// Reference formats a single issue reference, linking it when possible
+func (l *Links) Reference(reference commits.Reference) string {
+	if link := l.ReferenceURL(reference); link != "" {
+		return fmt.Sprintf("[%s](%s)", reference.Text, link)
+	}
+	return reference.Text
+}
+
+// linkTrackerMatches links the references of one tracker in a text without links
+func linkTrackerMatches(tracker *linkTracker, text string) string {
+	var builder strings.Builder
+	last := 0
+	idIndex := tracker.regex.SubexpIndex("id")
+
+	for _, match := range tracker.regex.FindAllStringSubmatchIndex(text, -1) {
+		id := text[match[0]:match[1]]
+		if idIndex >= 0 && match[2*idIndex] >= 0 {
+			id = text[match[2*idIndex]:match[2*idIndex+1]]
+		}
+		builder.WriteString(text[last:match[0]])
+		builder.WriteString(fmt.Sprintf("[%s](%s)", text[match[0]:match[1]], strings.ReplaceAll(tracker.url, "{id}", id)))
+		last = match[1]
+	}
+
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// replaceOutsideLinks applies replace to the parts of text that are not markdown links
+func replaceOutsideLinks(text string, replace func(string) string) string {
+	var builder strings.Builder
+	last := 0
+
+	for _, link := range markdownLinkPattern.FindAllStringIndex(text, -1) {
+		builder.WriteString(replace(text[last:link[0]]))
+		builder.WriteString(text[link[0]:link[1]])
+		last = link[1]
+	}
+
+	builder.WriteString(replace(text[last:]))
+	return builder.String()
+}
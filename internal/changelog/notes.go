@@ -0,0 +1,141 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Supported release notes output formats
+const (
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatJSON     = "json"
+)
+
+// markdownLinkPattern matches inline markdown links
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+
+// notesJSON is the JSON representation of a single release's notes
+type notesJSON struct {
+	Version  string           `json:"version"`
+	Date     string           `json:"date,omitempty"`
+	Link     string           `json:"link,omitempty"`
+	Notes    []string         `json:"notes,omitempty"`
+	Sections []notesGroupJSON `json:"sections"`
+}
+
+type notesGroupJSON struct {
+	Title   string           `json:"title"`
+	Parent  string           `json:"parent,omitempty"`
+	Entries []notesEntryJSON `json:"entries"`
+}
+
+type notesEntryJSON struct {
+	Scope       string   `json:"scope,omitempty"`
+	Description string   `json:"description"`
+	Hash        string   `json:"hash,omitempty"`
+	Details     []string `json:"details,omitempty"`
+}
+
+// ToSection converts a generated release into its parsed changelog section
+func (g *Generator) ToSection(release *Release) *Section {
+	return parseSection(g.FormatRelease(release))
+}
+
+// RenderNotes renders a release section as markdown, plain text or JSON
+func RenderNotes(section *Section, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatMarkdown, "md":
+		return section.Body() + "\n", nil
+	case FormatText, "txt":
+		return renderNotesText(section), nil
+	case FormatJSON:
+		return renderNotesJSON(section)
+	default:
+		return "", fmt.Errorf("unsupported notes format '%s' (must be: markdown, text, or json)", format)
+	}
+}
+
+// renderNotesText renders a section without markdown syntax
+func renderNotesText(section *Section) string {
+	var builder strings.Builder
+
+	for _, note := range section.Notes {
+		if strings.TrimSpace(note) != "" {
+			builder.WriteString(strings.TrimSpace(note) + "\n")
+		}
+	}
+	if builder.Len() > 0 {
+		builder.WriteString("\n")
+	}
+
+	for _, group := range section.Groups {
+		// Nested groups are indented below their parent
+		indent := ""
+		if group.Parent != "" {
+			indent = "  "
+		}
+		builder.WriteString(indent + strings.Trim(group.Title, "# ") + ":\n")
+		if len(group.Entries) == 0 {
+			continue
+		}
+		for _, entry := range group.Entries {
+			builder.WriteString(indent + "- ")
+			if entry.Scope != "" {
+				builder.WriteString(entry.Scope + ": ")
+			}
+			builder.WriteString(markdownLinkPattern.ReplaceAllString(entry.Description, "$1"))
+			if entry.Hash != "" {
+				builder.WriteString(fmt.Sprintf(" (%s)", entry.Hash))
+			}
+			builder.WriteString("\n")
+			for _, detail := range entry.Details {
+				builder.WriteString(indent + "  " + strings.TrimSpace(detail) + "\n")
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// renderNotesJSON renders a section as an indented JSON document
+func renderNotesJSON(section *Section) (string, error) {
+	notes := notesJSON{
+		Version:  section.Version,
+		Link:     section.Link,
+		Sections: []notesGroupJSON{},
+	}
+	if !section.Date.IsZero() {
+		notes.Date = section.Date.Format("2006-01-02")
+	}
+	for _, note := range section.Notes {
+		if strings.TrimSpace(note) != "" {
+			notes.Notes = append(notes.Notes, strings.TrimSpace(note))
+		}
+	}
+
+	for _, group := range section.Groups {
+		groupJSON := notesGroupJSON{Title: group.Title, Parent: group.Parent, Entries: []notesEntryJSON{}}
+		for _, entry := range group.Entries {
+			entryJSON := notesEntryJSON{
+				Scope:       entry.Scope,
+				Description: entry.Description,
+				Hash:        entry.Hash,
+			}
+			for _, detail := range entry.Details {
+				entryJSON.Details = append(entryJSON.Details, strings.TrimSpace(detail))
+			}
+			groupJSON.Entries = append(groupJSON.Entries, entryJSON)
+		}
+		notes.Sections = append(notes.Sections, groupJSON)
+	}
+
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode notes: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,366 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Section is a single "## [version]" block of a changelog
+type Section struct {
+	Version string
+	Date    time.Time // Zero when the heading carries no date
+	Link    string    // Link target of the version heading, if any
+	Notes   []string  // Lines between the heading and the first group
+	Groups  []*Group
+	Content string // Full markdown of the section, including its heading
+}
+
+// Group is a "### Title" block inside a release section, or a nested "#### Title" block
+type Group struct {
+	Title   string
+	Level   int    // Heading level, 3 or 4
+	Parent  string // Title of the enclosing group for nested groups
+	Entries []*Entry
+}
+
+// Entry is a single list item, or a paragraph line, inside a group
+type Entry struct {
+	Bullet      string // "* " or "- ", empty for paragraph text
+	Text        string // Entry text without the bullet
+	Scope       string
+	Description string
+	Hash        string   // Short commit hash when the entry was generated from a commit
+	Details     []string // Indented continuation lines
+}
+
+// Titles of the groups herald generates without a commit hash on every entry
+const (
+	breakingGroupTitle     = "⚠ BREAKING CHANGES"
+	revertedGroupTitle     = "Reverted"
+	contributorsGroupTitle = "Contributors"
+)
+
+// generatedGroups are regenerated as a whole, their entries are never hand-written
+var generatedGroups = map[string]bool{
+	breakingGroupTitle:     true,
+	revertedGroupTitle:     true,
+	contributorsGroupTitle: true,
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?(?:\(([^)]*)\))?(?:\s*[-–(]\s*(\d{4}-\d{2}-\d{2})\)?)?`)
+	scopePattern   = regexp.MustCompile(`^\*\*([^*]+?):\*\*\s+`)
+	hashPattern    = regexp.MustCompile(`\s*\(\[?([0-9a-f]{7,40})\]?(?:\([^)]*\))?\)\s*$`)
+)
+
+// ParseDocument parses changelog markdown into its header and release sections
+func ParseDocument(content string) *Document {
+	doc := &Document{}
+	lines := strings.Split(content, "\n")
+
+	var header strings.Builder
+	var body strings.Builder
+	inSection := false
+
+	flush := func() {
+		if !inSection {
+			return
+		}
+		section := parseSection(body.String())
+		if strings.EqualFold(section.Version, unreleasedVersion) {
+			doc.Unreleased = section
+		} else {
+			doc.Sections = append(doc.Sections, section)
+		}
+		body.Reset()
+	}
+
+	for i, line := range lines {
+		// Avoid adding a newline for the final, unterminated line
+		if i == len(lines)-1 && line == "" {
+			break
+		}
+
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			inSection = true
+		}
+
+		if !inSection {
+			header.WriteString(line + "\n")
+		} else {
+			body.WriteString(line + "\n")
+		}
+	}
+	flush()
+
+	doc.Header = header.String()
+	return doc
+}
+
+// parseSection parses the markdown of a single release section
+func parseSection(content string) *Section {
+	section := &Section{Content: content}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) == 0 {
+		return section
+	}
+
+	if matches := headingPattern.FindStringSubmatch(lines[0]); matches != nil {
+		section.Version = matches[1]
+		section.Link = matches[2]
+		if matches[3] != "" {
+			if date, err := time.Parse("2006-01-02", matches[3]); err == nil {
+				section.Date = date
+			}
+		}
+	}
+
+	var group *Group
+	var entry *Entry
+	parent := ""
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			entry = nil
+			if group == nil {
+				section.Notes = append(section.Notes, line) // Keeps paragraphs apart
+			}
+		case strings.HasPrefix(line, "### "):
+			group = &Group{Title: strings.TrimSpace(strings.TrimPrefix(line, "### ")), Level: 3}
+			section.Groups = append(section.Groups, group)
+			parent = group.Title
+			entry = nil
+		case strings.HasPrefix(line, "#### "):
+			group = &Group{Title: strings.TrimSpace(strings.TrimPrefix(line, "#### ")), Level: 4, Parent: parent}
+			section.Groups = append(section.Groups, group)
+			entry = nil
+		case group == nil:
+			section.Notes = append(section.Notes, line)
+		case entry != nil && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")):
+			entry.Details = append(entry.Details, line)
+		case strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "- "):
+			entry = parseEntry(line[2:])
+			entry.Bullet = line[:2]
+			group.Entries = append(group.Entries, entry)
+		default:
+			entry = nil
+			group.Entries = append(group.Entries, &Entry{Text: line, Description: trimmed})
+		}
+	}
+
+	return section
+}
+
+// parseEntry splits an entry into its scope, description and commit hash
+func parseEntry(text string) *Entry {
+	entry := &Entry{Text: text}
+	description := text
+
+	if matches := scopePattern.FindStringSubmatch(description); matches != nil {
+		entry.Scope = matches[1]
+		description = description[len(matches[0]):]
+	}
+
+	if loc := hashPattern.FindStringSubmatchIndex(description); loc != nil {
+		entry.Hash = description[loc[2]:loc[3]]
+		description = description[:loc[0]]
+	}
+
+	entry.Description = strings.TrimSpace(description)
+	return entry
+}
+
+// Release returns the parsed section for the given version, or nil
+func (d *Document) Release(ver string) *Section {
+	if strings.EqualFold(ver, unreleasedVersion) {
+		return d.Unreleased
+	}
+	if idx := d.FindSection(ver); idx >= 0 {
+		return d.Sections[idx]
+	}
+	return nil
+}
+
+// Body returns the section markdown without its heading
+func (s *Section) Body() string {
+	content := s.Content
+	if idx := strings.Index(content, "\n"); idx >= 0 {
+		return strings.TrimSpace(content[idx+1:])
+	}
+	return ""
+}
+
+// mergeHandWritten copies entries without a commit hash from a previous version
+// of the section, leaving out the generated groups. Returns true if anything was added.
+func (s *Section) mergeHandWritten(previous *Section) bool {
+	merged := false
+
+	for _, note := range previous.Notes {
+		if !containsLine(s.Notes, note) {
+			s.Notes = append(s.Notes, note)
+			merged = true
+		}
+	}
+
+	for _, previousGroup := range previous.Groups {
+		if previousGroup.Level <= 3 && generatedGroups[previousGroup.Title] {
+			continue
+		}
+		group := s.group(previousGroup)
+		for _, entry := range previousGroup.Entries {
+			if entry.Hash != "" || group.hasEntry(entry) {
+				continue
+			}
+			if !s.hasGroup(group) {
+				s.addGroup(group)
+			}
+			group.Entries = append(group.Entries, entry)
+			merged = true
+		}
+	}
+
+	return merged
+}
+
+// mergeUnreleased moves the notes collected under Unreleased into a generated section.
+// Paragraphs and hand-written groups come before the generated groups, and a group
+// whose title matches a generated group is merged into it.
+func (s *Section) mergeUnreleased(unreleased *Section) {
+	s.Notes = append(append([]string{}, unreleased.Notes...), append([]string{""}, s.Notes...)...)
+
+	var leading []*Group
+	for _, unreleasedGroup := range unreleased.Groups {
+		group := s.group(unreleasedGroup)
+		if s.hasGroup(group) {
+			for _, entry := range unreleasedGroup.Entries {
+				if !group.hasEntry(entry) {
+					group.Entries = append(group.Entries, entry)
+				}
+			}
+			continue
+		}
+
+		// Nested groups stay with their generated parent
+		if unreleasedGroup.Parent != "" && s.hasGroup(s.group(&Group{Title: unreleasedGroup.Parent})) {
+			s.addGroup(unreleasedGroup)
+			continue
+		}
+		leading = append(leading, unreleasedGroup)
+	}
+	s.Groups = append(leading, s.Groups...)
+}
+
+// group returns the group matching the title and parent of another group, or a new detached group
+func (s *Section) group(match *Group) *Group {
+	for _, group := range s.Groups {
+		if group.Title == match.Title && group.Parent == match.Parent {
+			return group
+		}
+	}
+	return &Group{Title: match.Title, Level: match.Level, Parent: match.Parent}
+}
+
+// addGroup adds a group at the end of the section, or after the existing
+// nested groups of its parent
+func (s *Section) addGroup(group *Group) {
+	if group.Parent == "" {
+		s.Groups = append(s.Groups, group)
+		return
+	}
+
+	insertAt := -1
+	for i, existing := range s.Groups {
+		if existing.Level <= 3 && existing.Title == group.Parent {
+			insertAt = i + 1
+		} else if insertAt == i && existing.Parent == group.Parent {
+			insertAt = i + 1
+		}
+	}
+
+	if insertAt < 0 {
+		s.Groups = append(s.Groups, &Group{Title: group.Parent, Level: 3}, group)
+		return
+	}
+	s.Groups = append(s.Groups[:insertAt], append([]*Group{group}, s.Groups[insertAt:]...)...)
+}
+
+// hasGroup reports whether the group is part of the section
+func (s *Section) hasGroup(group *Group) bool {
+	for _, existing := range s.Groups {
+		if existing == group {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEntry reports whether the group already contains an entry with the same text
+func (g *Group) hasEntry(entry *Entry) bool {
+	for _, existing := range g.Entries {
+		if existing.Text == entry.Text {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLine reports whether lines contains line, ignoring surrounding whitespace
+func containsLine(lines []string, line string) bool {
+	for _, existing := range lines {
+		if strings.TrimSpace(existing) == strings.TrimSpace(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown renders the parsed section back to markdown
+func (s *Section) Markdown() string {
+	var builder strings.Builder
+
+	heading := strings.SplitN(s.Content, "\n", 2)[0]
+	builder.WriteString(heading + "\n\n")
+
+	// Paragraphs stay separated by a single blank line
+	var notes []string
+	for _, note := range s.Notes {
+		if strings.TrimSpace(note) == "" {
+			if len(notes) > 0 && notes[len(notes)-1] != "" {
+				notes = append(notes, "")
+			}
+			continue
+		}
+		notes = append(notes, note)
+	}
+	if len(notes) > 0 && notes[len(notes)-1] == "" {
+		notes = notes[:len(notes)-1]
+	}
+	if len(notes) > 0 {
+		builder.WriteString(strings.Join(notes, "\n") + "\n\n")
+	}
+
+	for _, group := range s.Groups {
+		level := group.Level
+		if level == 0 {
+			level = 3
+		}
+		builder.WriteString(strings.Repeat("#", level) + " " + group.Title + "\n\n")
+		for i, entry := range group.Entries {
+			// Keep paragraphs separated from surrounding list items
+			if i > 0 && (entry.Bullet == "") != (group.Entries[i-1].Bullet == "") {
+				builder.WriteString("\n")
+			}
+			builder.WriteString(entry.Bullet + entry.Text + "\n")
+			for _, detail := range entry.Details {
+				builder.WriteString(detail + "\n")
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
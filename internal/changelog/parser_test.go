@@ -0,0 +1,242 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+const existingChangelog = `# Changelog
+
+Notes about this project.
+
+## [Unreleased]
+
+Upgrade notes for the next release.
+
+### Added
+
+* Hand-written entry
+
+## [1.1.0](https://github.com/o/r/compare/v1.0.0...v1.1.0) - 2024-03-01
+
+### Features
+
+* **api:** add export ([abc1234](https://github.com/o/r/commit/abc1234))
+  Exports CSV files.
+* Mention the export in the docs
+
+## [1.0.0] - 2024-01-15
+
+### Bug Fixes
+
+* fix crash (def5678)
+`
+
+func TestParseDocument(t *testing.T) {
+	doc := ParseDocument(existingChangelog)
+
+	if !strings.Contains(doc.Header, "Notes about this project.") {
+		t.Errorf("Header = %q, want the text before the first section", doc.Header)
+	}
+	if doc.Unreleased == nil {
+		t.Fatal("Unreleased = nil, want the Unreleased section")
+	}
+	if got := doc.UnreleasedNotes(); !strings.HasPrefix(got, "Upgrade notes") {
+		t.Errorf("UnreleasedNotes() = %q", got)
+	}
+	if len(doc.Sections) != 2 {
+		t.Fatalf("len(Sections) = %d, want 2", len(doc.Sections))
+	}
+
+	section := doc.Release("v1.1.0")
+	if section == nil {
+		t.Fatal("Release(v1.1.0) = nil")
+	}
+	if section.Link != "https://github.com/o/r/compare/v1.0.0...v1.1.0" {
+		t.Errorf("Link = %q", section.Link)
+	}
+	if got := section.Date.Format("2006-01-02"); got != "2024-03-01" {
+		t.Errorf("Date = %s, want 2024-03-01", got)
+	}
+	if len(section.Groups) != 1 || len(section.Groups[0].Entries) != 2 {
+		t.Fatalf("Groups = %+v, want one group with two entries", section.Groups)
+	}
+
+	generated := section.Groups[0].Entries[0]
+	if generated.Scope != "api" || generated.Description != "add export" || generated.Hash != "abc1234" {
+		t.Errorf("entry = scope %q, description %q, hash %q", generated.Scope, generated.Description, generated.Hash)
+	}
+	if len(generated.Details) != 1 {
+		t.Errorf("Details = %q, want the indented line", generated.Details)
+	}
+	if handWritten := section.Groups[0].Entries[1]; handWritten.Hash != "" {
+		t.Errorf("hand-written entry Hash = %q, want none", handWritten.Hash)
+	}
+
+	if got := doc.Release("1.0.0").Groups[0].Entries[0].Hash; got != "def5678" {
+		t.Errorf("plain hash = %q, want def5678", got)
+	}
+
+	if got := doc.String(); got != existingChangelog {
+		t.Errorf("String() does not round-trip:\n%s", got)
+	}
+}
+
+func TestSetReleaseKeepsHandWrittenEntries(t *testing.T) {
+	doc := ParseDocument(existingChangelog)
+	doc.Unreleased = nil
+
+	regenerated := "## [1.1.0] - 2024-03-01\n\n" +
+		"### Features\n\n" +
+		"* **api:** add CSV export ([abc1234](https://github.com/o/r/commit/abc1234))\n\n"
+	doc.SetRelease("1.1.0", regenerated, false)
+
+	section := doc.Release("1.1.0")
+	want := "## [1.1.0] - 2024-03-01\n\n" +
+		"### Features\n\n" +
+		"* **api:** add CSV export ([abc1234](https://github.com/o/r/commit/abc1234))\n" +
+		"* Mention the export in the docs\n\n"
+	if section.Content != want {
+		t.Errorf("Content =\n%s\nwant\n%s", section.Content, want)
+	}
+	if len(doc.Sections) != 2 {
+		t.Errorf("len(Sections) = %d, want the section replaced in place", len(doc.Sections))
+	}
+}
+
+func TestSetReleaseSkipsGeneratedGroups(t *testing.T) {
+	previous := "## [2.0.0] - 2024-05-01\n\n" +
+		"### ⚠ BREAKING CHANGES\n\n" +
+		"* drop the v1 API (**api**)\n\n" +
+		"### Features\n\n" +
+		"* remove v1 (1234567)\n\n" +
+		"### Reverted\n\n" +
+		"* feat: beta flag (2345678), reverted by (3456789)\n\n" +
+		"### Contributors\n\n" +
+		"* @bee (first contribution)\n"
+	doc := ParseDocument(previous)
+
+	regenerated := "## [2.0.0] - 2024-05-01\n\n" +
+		"### ⚠ BREAKING CHANGES\n\n" +
+		"* remove the v1 API (**api**)\n\n" +
+		"### Features\n\n" +
+		"* remove v1 (1234567)\n\n" +
+		"### Contributors\n\n" +
+		"* B (first contribution)\n\n"
+	doc.SetRelease("2.0.0", regenerated, false)
+
+	if got := doc.Release("2.0.0").Content; got != regenerated {
+		t.Errorf("Content =\n%s\nwant the regenerated section\n%s", got, regenerated)
+	}
+}
+
+func TestSetReleaseMovesUnreleasedNotes(t *testing.T) {
+	doc := ParseDocument(existingChangelog)
+
+	generated := "## [1.2.0] - 2024-04-01\n\n" +
+		"### Added\n\n" +
+		"* Generated entry (1234567)\n\n" +
+		"### Features\n\n" +
+		"* new feature (2345678)\n\n"
+	doc.SetRelease("1.2.0", generated, true)
+
+	want := "## [1.2.0] - 2024-04-01\n\n" +
+		"Upgrade notes for the next release.\n\n" +
+		"### Added\n\n" +
+		"* Generated entry (1234567)\n" +
+		"* Hand-written entry\n\n" +
+		"### Features\n\n" +
+		"* new feature (2345678)\n\n"
+	if got := doc.Release("1.2.0").Content; got != want {
+		t.Errorf("Content =\n%s\nwant\n%s", got, want)
+	}
+	if doc.UnreleasedNotes() != "" {
+		t.Errorf("UnreleasedNotes() = %q, want an empty Unreleased section", doc.UnreleasedNotes())
+	}
+}
+
+func TestMergeUnreleased(t *testing.T) {
+	section := parseSection("## [1.2.0]\n\n" +
+		"### Features\n\n" +
+		"* new feature (2345678)\n\n" +
+		"#### api\n\n" +
+		"* endpoint (3456789)\n")
+	unreleased := parseSection("## [Unreleased]\n\n" +
+		"First paragraph.\n\n" +
+		"Second paragraph.\n\n" +
+		"### Migration\n\n" +
+		"* Run the migration\n\n" +
+		"#### cli\n\n" +
+		"* New flag\n")
+	unreleased.Groups[1].Parent = "Features"
+
+	section.mergeUnreleased(unreleased)
+
+	want := "## [1.2.0]\n\n" +
+		"First paragraph.\n\n" +
+		"Second paragraph.\n\n" +
+		"### Migration\n\n" +
+		"* Run the migration\n\n" +
+		"### Features\n\n" +
+		"* new feature (2345678)\n\n" +
+		"#### api\n\n" +
+		"* endpoint (3456789)\n\n" +
+		"#### cli\n\n" +
+		"* New flag\n\n"
+	if got := section.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMergeHandWritten(t *testing.T) {
+	previous := parseSection("## [1.0.0]\n\n" +
+		"A note.\n\n" +
+		"### Features\n\n" +
+		"* old generated (1234567)\n" +
+		"* Hand-written feature\n\n" +
+		"### Thanks\n\n" +
+		"* Everyone\n")
+	section := parseSection("## [1.0.0]\n\n" +
+		"### Features\n\n" +
+		"* new generated (1234567)\n")
+
+	if !section.mergeHandWritten(previous) {
+		t.Fatal("mergeHandWritten() = false, want entries added")
+	}
+	want := "## [1.0.0]\n\n" +
+		"A note.\n\n" +
+		"### Features\n\n" +
+		"* new generated (1234567)\n" +
+		"* Hand-written feature\n\n" +
+		"### Thanks\n\n" +
+		"* Everyone\n\n"
+	if got := section.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	if section.mergeHandWritten(previous) {
+		t.Error("second mergeHandWritten() = true, want nothing added")
+	}
+}
+
+func TestSetReleaseIsIdempotent(t *testing.T) {
+	generated := "## [1.2.0] - 2024-04-01\n\n" +
+		"### ⚠ BREAKING CHANGES\n\n" +
+		"* drop v1 (**api**)\n\n" +
+		"### Added\n\n" +
+		"* Generated entry (1234567)\n\n" +
+		"### Contributors\n\n" +
+		"* @bee\n\n"
+
+	doc := ParseDocument(existingChangelog)
+	doc.SetRelease("1.2.0", generated, true)
+	first := doc.String()
+
+	for i := 0; i < 2; i++ {
+		doc = ParseDocument(doc.String())
+		doc.SetRelease("1.2.0", generated, true)
+		if got := doc.String(); got != first {
+			t.Fatalf("run %d changed the changelog:\n%s\nwant\n%s", i+2, got, first)
+		}
+	}
+}
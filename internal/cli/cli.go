@@ -2,12 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"herald/internal/changelog"
 	"herald/internal/commits"
 	"herald/internal/config"
 	"herald/internal/git"
+	"herald/internal/hooks"
+	"herald/internal/lock"
 	"herald/internal/version"
 
 	"github.com/spf13/cobra"
@@ -37,15 +40,17 @@ and manage semantic versioning.`,
 }
 
 var (
-	cfgFile     string
-	dryRun      bool
-	nextVersion bool
+	cfgFile          string
+	dryRun           bool
+	nextVersion      bool
+	rebuildChangelog bool
 )
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .heraldrc)")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "preview changes without applying them")
 	rootCmd.PersistentFlags().BoolVar(&nextVersion, "next-version", false, "output only the next version number")
+	changelogCmd.Flags().BoolVar(&rebuildChangelog, "rebuild", false, "regenerate the full changelog from all version tags")
 
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
@@ -109,6 +114,9 @@ func runRelease(cfg *config.Config, dryRun bool) error {
 }
 
 func runChangelog(cfg *config.Config, dryRun bool) error {
+	if rebuildChangelog {
+		return executeChangelogRebuild(cfg, dryRun)
+	}
 	return executeChangelog(cfg, dryRun)
 }
 
@@ -119,9 +127,34 @@ func runVersionBump(cfg *config.Config) error {
 // executeRelease implements the main release functionality
 func executeRelease(cfg *config.Config, dryRun bool) error {
 	// Open git repository
-	repo, err := git.OpenRepository(".")
+	repo, err := openRepository(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+		return err
+	}
+
+	// Keep concurrent releases of the repository apart, before anything is read
+	if !dryRun {
+		releaseLock, err := lock.New(cfg, repo)
+		if err != nil {
+			return err
+		}
+		waited, err := releaseLock.Acquire()
+		if err != nil {
+			return fmt.Errorf("failed to take the release lock: %w", err)
+		}
+		defer func() {
+			if err := releaseLock.Release(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}()
+
+		// The release is computed after the other release finished, from its tags when it ran elsewhere
+		if waited && cfg.Lock.OnContention == "retry" && cfg.Lock.Remote {
+			fmt.Printf("Fetching tags from %s to recompute the release\n", cfg.Lock.RemoteName)
+			if err := repo.FetchTags(cfg.Lock.RemoteName); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Check if working directory is clean
@@ -176,24 +209,64 @@ func executeRelease(cfg *config.Config, dryRun bool) error {
 
 	// Parse conventional commits
 	parser := commits.NewParser(cfg)
-	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
 	if err != nil {
-		return fmt.Errorf("failed to parse commits: %w", err)
+		return err
 	}
 
-	// Calculate version bump
+	previousVersion := ""
+	if latestTag != nil {
+		previousVersion = currentVersion.String()
+	}
+
+	// From here on a failure undoes what the release and its hooks changed
+	rollback, err := newReleaseRollback(repo, dryRun)
+	if err != nil {
+		return err
+	}
+	hookRunner := hooks.NewRunner(cfg, dryRun)
+	hookEnv := hooks.Env{PreviousVersion: previousVersion}
+
+	// Calculate version bump, before any hook runs
 	bumpType := parser.CalculateBumpType(conventionalCommits)
 	if bumpType == commits.None {
 		fmt.Println("No significant changes found, no release needed")
 		return nil
 	}
 
+	if err := hookRunner.Run(hooks.PreVersion, hookEnv); err != nil {
+		return rollback.restore(err)
+	}
+
 	nextVersion := versionManager.CalculateNextVersion(currentVersion, bumpType)
 	fmt.Printf("Next version: %s (bump type: %s)\n", nextVersion.String(), bumpType.String())
 
+	// Plugins may choose another version, add notes or veto the release
+	plugins := newReleasePlugins(cfg, previousVersion, bumpType, conventionalCommits, dryRun)
+	nextVersion, pluginNotes, err := plugins.version(versionManager, currentVersion, nextVersion)
+	if err != nil {
+		return rollback.restore(err)
+	}
+
+	hookEnv.Version = nextVersion.String()
+	hookEnv.Bump = plugins.bump.String()
+	if err := hookRunner.Run(hooks.PostVersion, hookEnv); err != nil {
+		return rollback.restore(err)
+	}
+
 	// Generate changelog
 	changelogGenerator := changelog.NewGenerator(cfg)
 	release := changelogGenerator.GenerateRelease(nextVersion, conventionalCommits)
+	if latestTag != nil {
+		release.PreviousTag = latestTag.Name
+	}
+	if err := markFirstTimeContributors(repo, changelogGenerator, release); err != nil {
+		return rollback.restore(err)
+	}
+	release.Notes = append(release.Notes, pluginNotes...)
+	if err := plugins.notes(changelogGenerator, release); err != nil {
+		return rollback.restore(err)
+	}
 
 	// Show preview
 	stats := changelogGenerator.GetChangelogStats(release)
@@ -206,42 +279,82 @@ func executeRelease(cfg *config.Config, dryRun bool) error {
 		}
 	}
 
+	tagName := versionManager.FormatTagName(nextVersion)
+	tagMessage := strings.ReplaceAll(cfg.Git.TagMessage, "{version}", nextVersion.String())
+
 	if dryRun {
 		fmt.Printf("\n=== DRY RUN MODE ===\n")
-		fmt.Printf("Would create tag: %s\n", versionManager.FormatTagName(nextVersion))
+		if err := hookRunner.Run(hooks.PreChangelog, hookEnv); err != nil {
+			return err
+		}
 		fmt.Printf("Would update changelog: %s\n", cfg.Changelog.File)
+		if err := hookRunner.Run(hooks.PostChangelog, hookEnv); err != nil {
+			return err
+		}
+		if err := hookRunner.Run(hooks.PreTag, hookEnv); err != nil {
+			return err
+		}
+		fmt.Printf("Would create tag: %s\n", tagName)
+		if err := hookRunner.Run(hooks.PostTag, hookEnv); err != nil {
+			return err
+		}
 		fmt.Printf("\nChangelog preview:\n")
 		fmt.Print(changelogGenerator.PreviewRelease(release))
-		return nil
+		if err := publishRelease(cfg, repo, changelogGenerator, release, true, false); err != nil {
+			return err
+		}
+		return hookRunner.Run(hooks.PostRelease, hookEnv)
 	}
 
-	// Create git tag
-	tagName := versionManager.FormatTagName(nextVersion)
-	tagMessage := strings.ReplaceAll(cfg.Git.TagMessage, "{version}", nextVersion.String())
-	
-	fmt.Printf("\nCreating git tag: %s\n", tagName)
-	err = repo.CreateTag(tagName, tagMessage)
-	if err != nil {
-		return fmt.Errorf("failed to create git tag: %w", err)
+	// Update changelog
+	if err := hookRunner.Run(hooks.PreChangelog, hookEnv); err != nil {
+		return rollback.restore(err)
+	}
+	fmt.Printf("\nUpdating changelog: %s\n", cfg.Changelog.File)
+	if err := changelogGenerator.UpsertRelease(release); err != nil {
+		return rollback.restore(fmt.Errorf("failed to update changelog: %w", err))
 	}
 
-	// Update changelog
-	fmt.Printf("Updating changelog: %s\n", cfg.Changelog.File)
-	err = changelogGenerator.PrependRelease(release)
-	if err != nil {
-		return fmt.Errorf("failed to update changelog: %w", err)
+	if err := hookRunner.Run(hooks.PostChangelog, hookEnv); err != nil {
+		return rollback.restore(err)
+	}
+
+	// Create git tag, the changelog and the files changed by hooks are left for the user to commit
+	if err := hookRunner.Run(hooks.PreTag, hookEnv); err != nil {
+		return rollback.restore(err)
+	}
+	fmt.Printf("Creating git tag: %s\n", tagName)
+	if err := repo.CreateTag(tagName, tagMessage); err != nil {
+		return rollback.restore(fmt.Errorf("failed to create git tag: %w", err))
+	}
+	rollback.tag = tagName
+	if err := hookRunner.Run(hooks.PostTag, hookEnv); err != nil {
+		return rollback.restore(err)
 	}
 
 	fmt.Printf("\n✅ Release %s completed successfully!\n", nextVersion.String())
+
+	// The release is done at this point, failed publishers can be resumed with "herald publish --retry"
+	if err := autoPublish(cfg, repo, changelogGenerator, release); err != nil {
+		return fmt.Errorf("%w (run 'herald publish --retry %s' to resume)", err, nextVersion.String())
+	}
+
+	if err := plugins.released(changelogGenerator, release); err != nil {
+		return fmt.Errorf("%w (release %s is complete)", err, nextVersion.String())
+	}
+
+	if err := hookRunner.Run(hooks.PostRelease, hookEnv); err != nil {
+		return fmt.Errorf("%w (release %s is complete)", err, nextVersion.String())
+	}
 	return nil
 }
 
 // executeChangelog generates changelog only
 func executeChangelog(cfg *config.Config, dryRun bool) error {
 	// Open git repository
-	repo, err := git.OpenRepository(".")
+	repo, err := openRepository(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+		return err
 	}
 
 	// Get latest tag
@@ -283,9 +396,9 @@ func executeChangelog(cfg *config.Config, dryRun bool) error {
 
 	// Parse conventional commits
 	parser := commits.NewParser(cfg)
-	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
 	if err != nil {
-		return fmt.Errorf("failed to parse commits: %w", err)
+		return err
 	}
 
 	// Calculate version bump for the changelog
@@ -295,6 +408,12 @@ func executeChangelog(cfg *config.Config, dryRun bool) error {
 	// Generate changelog
 	changelogGenerator := changelog.NewGenerator(cfg)
 	release := changelogGenerator.GenerateRelease(nextVersion, conventionalCommits)
+	if latestTag != nil {
+		release.PreviousTag = latestTag.Name
+	}
+	if err := markFirstTimeContributors(repo, changelogGenerator, release); err != nil {
+		return err
+	}
 
 	if dryRun {
 		fmt.Print(changelogGenerator.PreviewRelease(release))
@@ -303,7 +422,7 @@ func executeChangelog(cfg *config.Config, dryRun bool) error {
 
 	// Update changelog
 	fmt.Printf("Updating changelog: %s\n", cfg.Changelog.File)
-	err = changelogGenerator.PrependRelease(release)
+	err = changelogGenerator.UpsertRelease(release)
 	if err != nil {
 		return fmt.Errorf("failed to update changelog: %w", err)
 	}
@@ -319,9 +438,9 @@ func executeVersionBump(cfg *config.Config) error {
 		return executeNextVersionOnly(cfg)
 	}
 	// Open git repository
-	repo, err := git.OpenRepository(".")
+	repo, err := openRepository(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+		return err
 	}
 
 	// Get latest tag
@@ -365,13 +484,14 @@ func executeVersionBump(cfg *config.Config) error {
 
 	// Parse conventional commits
 	parser := commits.NewParser(cfg)
-	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
 	if err != nil {
-		return fmt.Errorf("failed to parse commits: %w", err)
+		return err
 	}
 
 	// Calculate version bump
-	bumpType := parser.CalculateBumpType(conventionalCommits)
+	explanation := parser.ExplainBump(conventionalCommits)
+	bumpType := explanation.Bump
 	
 	fmt.Printf("Commits since last release: %d\n", len(gitCommits))
 	
@@ -387,6 +507,17 @@ func executeVersionBump(cfg *config.Config) error {
 	if len(breakingChanges) > 0 {
 		fmt.Printf("- Breaking changes: %d\n", len(breakingChanges))
 	}
+
+	if len(explanation.Reverted) > 0 {
+		fmt.Printf("- Reverted within this release (ignored): %d\n", len(explanation.Reverted))
+	}
+
+	// Commits that a bump rule left out or changed
+	for _, decision := range explanation.Decisions {
+		if decision.Rule > 0 {
+			fmt.Printf("- %s: %s\n", decision.Commit.Original.Subject, decision.Reason)
+		}
+	}
 	
 	if bumpType == commits.None {
 		fmt.Println("\nNo significant changes found, no version bump needed")
@@ -395,6 +526,7 @@ func executeVersionBump(cfg *config.Config) error {
 
 	nextVersion := versionManager.CalculateNextVersion(currentVersion, bumpType)
 	fmt.Printf("\nRecommended version bump: %s\n", bumpType.String())
+	fmt.Printf("Decided by: %s (%s)\n", explanation.Decisive.Commit.Original.Subject, explanation.Decisive.Reason)
 	fmt.Printf("Next version: %s\n", nextVersion.String())
 	
 	// Show all possible version suggestions
@@ -412,9 +544,9 @@ func executeVersionBump(cfg *config.Config) error {
 // executeNextVersionOnly outputs only the next version number (for CI/CD integration)
 func executeNextVersionOnly(cfg *config.Config) error {
 	// Open git repository
-	repo, err := git.OpenRepository(".")
+	repo, err := openRepository(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open git repository: %w", err)
+		return err
 	}
 
 	// Get latest tag
@@ -458,9 +590,9 @@ func executeNextVersionOnly(cfg *config.Config) error {
 
 	// Parse conventional commits
 	parser := commits.NewParser(cfg)
-	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
 	if err != nil {
-		return fmt.Errorf("failed to parse commits: %w", err)
+		return err
 	}
 
 	// Calculate version bump
@@ -470,4 +602,120 @@ func executeNextVersionOnly(cfg *config.Config) error {
 	// Output only the version number (no newline for CI/CD piping)
 	fmt.Print(nextVersion.String())
 	return nil
-} 
\ No newline at end of file
+} 
+
+// openRepository opens the git repository in the current directory and applies
+// the repository related configuration
+func openRepository(cfg *config.Config) (*git.Repository, error) {
+	repo, err := git.OpenRepository(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	detectRepositoryURL(cfg, repo)
+
+	// Changed files are only needed by path based bump rules
+	logOptions := git.LogOptions{Files: cfg.Commits.UsesPaths()}
+	switch strings.ToLower(cfg.Commits.MergeStrategy) {
+	case "skip":
+		logOptions.NoMerges = true
+	case "first-parent", "parse":
+		logOptions.FirstParent = true
+	}
+	repo.SetLogOptions(logOptions)
+
+	return repo, nil
+}
+
+// detectRepositoryURL fills in the repository URL from the origin remote when it is not configured
+func detectRepositoryURL(cfg *config.Config, repo *git.Repository) {
+	if cfg.Repository.URL != "" {
+		return
+	}
+	if remoteURL, err := repo.GetRemoteURL("origin"); err == nil {
+		cfg.Repository.URL = remoteURL
+	}
+}
+
+// markFirstTimeContributors flags release contributors that do not appear in the history before the release
+func markFirstTimeContributors(repo *git.Repository, changelogGenerator *changelog.Generator, release *changelog.Release) error {
+	if len(release.Contributors) == 0 || release.PreviousTag == "" {
+		return nil
+	}
+
+	knownEmails, err := repo.GetAuthorEmails(release.PreviousTag)
+	if err != nil {
+		return fmt.Errorf("failed to check contributor history: %w", err)
+	}
+
+	changelogGenerator.MarkFirstTimeContributors(release, knownEmails)
+	return nil
+}
+
+// parseCommits parses git commits and applies the merge strategy and the non-conventional commit policy
+func parseCommits(repo *git.Repository, parser *commits.Parser, gitCommits []*git.Commit) ([]*commits.ConventionalCommit, error) {
+	gitCommits, err := expandMergeCommits(repo, parser, gitCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commits: %w", err)
+	}
+
+	kept, nonConventional := parser.FilterNonConventional(conventionalCommits)
+	if len(nonConventional) == 0 {
+		return kept, nil
+	}
+
+	switch parser.NonConventionalPolicy() {
+	case "warn":
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %d non-conventional commits:\n%s", len(nonConventional), formatCommitReport(nonConventional))
+	case "fail":
+		return nil, fmt.Errorf("found %d non-conventional commits (run 'herald lint' for details):\n%s", len(nonConventional), formatCommitReport(nonConventional))
+	}
+
+	return kept, nil
+}
+
+// parseHistoryCommits parses the commits of an existing release. Released history
+// cannot be cleaned up, so the non-conventional policy never warns or fails here.
+func parseHistoryCommits(repo *git.Repository, parser *commits.Parser, gitCommits []*git.Commit) ([]*commits.ConventionalCommit, error) {
+	gitCommits, err := expandMergeCommits(repo, parser, gitCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commits: %w", err)
+	}
+
+	conventionalCommits, _ = parser.FilterNonConventional(conventionalCommits)
+	return conventionalCommits, nil
+}
+
+// expandMergeCommits replaces merge commits without a conventional message by the
+// commits of the merged branch when the "parse" merge strategy is configured
+func expandMergeCommits(repo *git.Repository, parser *commits.Parser, gitCommits []*git.Commit) ([]*git.Commit, error) {
+	if parser.MergeStrategy() != "parse" {
+		return gitCommits, nil
+	}
+
+	var result []*git.Commit
+	for _, commit := range gitCommits {
+		if !commit.IsMerge() || parser.HasConventionalHeader(commit) {
+			result = append(result, commit)
+			continue
+		}
+
+		mergedCommits, err := repo.GetMergedCommits(commit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits merged by %s: %w", commit.Hash, err)
+		}
+		result = append(result, mergedCommits...)
+	}
+
+	return result, nil
+}
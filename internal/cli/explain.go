@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/git"
+	"herald/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var explainOutput string
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain how the next version is calculated",
+	Long: `Explain how the next version is calculated.
+
+Every commit since the latest tag is listed with its parsed type, scope and
+breaking flag, the keyword or rule that applied, and the bump it contributes.
+The commit that decided the final bump is marked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		return executeExplain(cfg)
+	},
+}
+
+func init() {
+	explainCmd.Flags().StringVarP(&explainOutput, "output", "o", "text", "output format: text or json")
+	rootCmd.AddCommand(explainCmd)
+}
+
+// explainJSON is the JSON form of a bump explanation
+type explainJSON struct {
+	CurrentVersion string              `json:"current_version"`
+	NextVersion    string              `json:"next_version"`
+	Bump           string              `json:"bump"`
+	DecidedBy      string              `json:"decided_by,omitempty"`
+	Commits        []explainCommitJSON `json:"commits"`
+	Reverted       []explainRevertJSON `json:"reverted"`
+}
+
+type explainCommitJSON struct {
+	Hash           string `json:"hash"`
+	Subject        string `json:"subject"`
+	Type           string `json:"type"`
+	Scope          string `json:"scope,omitempty"`
+	Breaking       bool   `json:"breaking"`
+	BreakingReason string `json:"breaking_reason,omitempty"`
+	Rule           int    `json:"rule,omitempty"`
+	Excluded       bool   `json:"excluded"`
+	Bump           string `json:"bump"`
+	Reason         string `json:"reason"`
+	Decisive       bool   `json:"decisive"`
+}
+
+type explainRevertJSON struct {
+	Hash       string `json:"hash"`
+	Subject    string `json:"subject"`
+	RevertedBy string `json:"reverted_by"`
+}
+
+// executeExplain prints the bump decision of every commit since the latest tag
+func executeExplain(cfg *config.Config) error {
+	if explainOutput != "text" && explainOutput != "json" {
+		return fmt.Errorf("unsupported output format: %s (must be: text or json)", explainOutput)
+	}
+
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Get latest tag
+	latestTag, err := repo.GetLatestTag()
+	if err != nil {
+		latestTag = nil
+	}
+
+	// Get current version
+	versionManager := version.NewManager(cfg)
+	var currentVersion *version.Version
+	if latestTag != nil {
+		currentVersion, err = versionManager.GetCurrentVersion(latestTag.Name)
+		if err != nil {
+			return fmt.Errorf("failed to parse current version: %w", err)
+		}
+	} else {
+		currentVersion, err = versionManager.GetInitialVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get initial version: %w", err)
+		}
+	}
+
+	// Get commits since last tag
+	var gitCommits []*git.Commit
+	if latestTag != nil {
+		gitCommits, err = repo.GetCommitsSinceTag(latestTag.Name)
+	} else {
+		gitCommits, err = repo.GetAllCommits()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	// Parse conventional commits
+	parser := commits.NewParser(cfg)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
+	if err != nil {
+		return err
+	}
+
+	explanation := parser.ExplainBump(conventionalCommits)
+	nextVersion := versionManager.CalculateNextVersion(currentVersion, explanation.Bump)
+
+	if explainOutput == "json" {
+		output, err := formatExplanationJSON(explanation, currentVersion, nextVersion)
+		if err != nil {
+			return err
+		}
+		fmt.Println(output)
+		return nil
+	}
+
+	fmt.Print(formatExplanationText(explanation, currentVersion, nextVersion))
+	return nil
+}
+
+// formatExplanationText renders a bump explanation for the terminal
+func formatExplanationText(explanation *commits.BumpExplanation, currentVersion, nextVersion *version.Version) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Current version: %s\n", currentVersion.String()))
+	builder.WriteString(fmt.Sprintf("Commits considered: %d\n\n", len(explanation.Decisions)))
+
+	for _, decision := range explanation.Decisions {
+		commit := decision.Commit
+		builder.WriteString(fmt.Sprintf("%s %s\n", shortHash(commit.Original.Hash), commit.Original.Subject))
+
+		scope := commit.Scope
+		if scope == "" {
+			scope = "-"
+		}
+		breaking := "no"
+		if commit.IsBreakingChange {
+			breaking = "yes, " + commit.BreakingReason
+		}
+		builder.WriteString(fmt.Sprintf("  type: %s  scope: %s  breaking: %s\n", commit.Type, scope, breaking))
+
+		bump := decision.Bump.String()
+		if decision.Excluded {
+			bump = "excluded"
+		}
+		builder.WriteString(fmt.Sprintf("  bump: %s, %s", bump, decision.Reason))
+		if decision == explanation.Decisive {
+			builder.WriteString("  <- decided the bump")
+		}
+		builder.WriteString("\n")
+	}
+
+	if len(explanation.Reverted) > 0 {
+		builder.WriteString("\nReverted within this release (ignored):\n")
+		for _, pair := range explanation.Reverted {
+			builder.WriteString(fmt.Sprintf("%s %s, reverted by %s\n",
+				shortHash(pair.Original.Original.Hash),
+				pair.Original.Original.Subject,
+				shortHash(pair.Revert.Original.Hash)))
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("\nBump: %s\n", explanation.Bump.String()))
+	if explanation.Decisive != nil {
+		builder.WriteString(fmt.Sprintf("Decided by: %s %s\n", shortHash(explanation.Decisive.Commit.Original.Hash), explanation.Decisive.Commit.Original.Subject))
+		builder.WriteString(fmt.Sprintf("Next version: %s\n", nextVersion.String()))
+	}
+
+	return builder.String()
+}
+
+// formatExplanationJSON renders a bump explanation as JSON
+func formatExplanationJSON(explanation *commits.BumpExplanation, currentVersion, nextVersion *version.Version) (string, error) {
+	result := explainJSON{
+		CurrentVersion: currentVersion.String(),
+		NextVersion:    nextVersion.String(),
+		Bump:           explanation.Bump.String(),
+		Commits:        []explainCommitJSON{},
+		Reverted:       []explainRevertJSON{},
+	}
+	if explanation.Decisive != nil {
+		result.DecidedBy = explanation.Decisive.Commit.Original.Hash
+	}
+
+	for _, decision := range explanation.Decisions {
+		commit := decision.Commit
+		result.Commits = append(result.Commits, explainCommitJSON{
+			Hash:           commit.Original.Hash,
+			Subject:        commit.Original.Subject,
+			Type:           commit.Type,
+			Scope:          commit.Scope,
+			Breaking:       commit.IsBreakingChange,
+			BreakingReason: commit.BreakingReason,
+			Rule:           decision.Rule,
+			Excluded:       decision.Excluded,
+			Bump:           decision.Bump.String(),
+			Reason:         decision.Reason,
+			Decisive:       decision == explanation.Decisive,
+		})
+	}
+
+	for _, pair := range explanation.Reverted {
+		result.Reverted = append(result.Reverted, explainRevertJSON{
+			Hash:       pair.Original.Original.Hash,
+			Subject:    pair.Original.Original.Subject,
+			RevertedBy: pair.Revert.Original.Hash,
+		})
+	}
+
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode explanation: %w", err)
+	}
+	return string(output), nil
+}
+
+// shortHash returns the abbreviated form of a commit hash
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
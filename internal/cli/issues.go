@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"herald/internal/changelog"
+	"herald/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	issuesOutput   string
+	issuesResolved bool
+)
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues [version]",
+	Short: "List the issues referenced by a release",
+	Long: `List the issues referenced by a release.
+
+Without a version, the issues of the upcoming release are listed. With a
+version, the issues referenced by the commits between that tag and the
+previous one are listed. Issues are recognized by the configured
+repository.trackers, including commit types hidden from the changelog.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		requested := ""
+		if len(args) > 0 {
+			requested = args[0]
+		}
+		return executeIssues(cfg, requested)
+	},
+}
+
+func init() {
+	issuesCmd.Flags().StringVarP(&issuesOutput, "output", "o", "text", "output format: text or json")
+	issuesCmd.Flags().BoolVar(&issuesResolved, "resolved", false, "only list issues closed, fixed or resolved by a commit")
+	rootCmd.AddCommand(issuesCmd)
+}
+
+// issuesJSON is the JSON form of the issues of a release
+type issuesJSON struct {
+	Version string             `json:"version"`
+	Issues  []*changelog.Issue `json:"issues"`
+}
+
+// executeIssues prints the issues referenced by the requested release
+func executeIssues(cfg *config.Config, requested string) error {
+	if issuesOutput != "text" && issuesOutput != "json" {
+		return fmt.Errorf("unsupported output format: %s (must be: text or json)", issuesOutput)
+	}
+
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	var release *changelog.Release
+	if requested == "" {
+		release, err = upcomingRelease(cfg, repo)
+	} else {
+		release, err = taggedRelease(cfg, repo, requested)
+		if err == nil && release == nil {
+			err = fmt.Errorf("version %s is not tagged", requested)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	issues := []*changelog.Issue{}
+	for _, issue := range release.Issues {
+		if issuesResolved && !issue.Resolved {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	if issuesOutput == "json" {
+		output, err := json.MarshalIndent(issuesJSON{Version: release.Version.String(), Issues: issues}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode issues: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Print(formatIssues(issues))
+	return nil
+}
+
+// formatIssues lists issues one per line: key, tracker, actions, URL and commits
+func formatIssues(issues []*changelog.Issue) string {
+	if len(issues) == 0 {
+		return "No issues referenced\n"
+	}
+
+	var builder strings.Builder
+	for _, issue := range issues {
+		var hashes []string
+		for _, hash := range issue.Commits {
+			hashes = append(hashes, shortHash(hash))
+		}
+		builder.WriteString(fmt.Sprintf("%s (%s) %s", issue.Text, issue.Tracker, strings.Join(issue.Actions, ", ")))
+		if issue.URL != "" {
+			builder.WriteString(" " + issue.URL)
+		}
+		builder.WriteString(fmt.Sprintf(" [%s]\n", strings.Join(hashes, " ")))
+	}
+	return builder.String()
+}
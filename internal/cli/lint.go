@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/git"
+
+	"github.com/spf13/cobra"
+)
+
+var lintAll bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Report commits that do not follow the commit conventions",
+	// A lint failure is not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		return executeLint(cfg)
+	},
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintAll, "all", false, "check the full history instead of the commits since the latest tag")
+	rootCmd.AddCommand(lintCmd)
+}
+
+// executeLint lists the non-conventional commits that would be released
+func executeLint(cfg *config.Config) error {
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Get commits since last tag, or all commits
+	var gitCommits []*git.Commit
+	latestTag, err := repo.GetLatestTag()
+	if lintAll || err != nil {
+		gitCommits, err = repo.GetAllCommits()
+	} else {
+		gitCommits, err = repo.GetCommitsSinceTag(latestTag.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	parser := commits.NewParser(cfg)
+	gitCommits, err = expandMergeCommits(repo, parser, gitCommits)
+	if err != nil {
+		return err
+	}
+
+	conventionalCommits, err := parser.ParseCommits(gitCommits)
+	if err != nil {
+		return fmt.Errorf("failed to parse commits: %w", err)
+	}
+
+	fmt.Printf("Checked %d commits\n", len(conventionalCommits))
+
+	_, nonConventional := parser.FilterNonConventional(conventionalCommits)
+	if len(nonConventional) > 0 {
+		fmt.Printf("\nNon-conventional commits (%d, policy: %s):\n", len(nonConventional), parser.NonConventionalPolicy())
+		fmt.Print(formatCommitReport(nonConventional))
+	}
+
+	var unknownScopes []*commits.ConventionalCommit
+	for _, commit := range conventionalCommits {
+		if !parser.IsAllowedScope(commit.Scope) {
+			unknownScopes = append(unknownScopes, commit)
+		}
+	}
+	if len(unknownScopes) > 0 {
+		fmt.Printf("\nCommits with scopes not listed in commits.scopes (%d):\n", len(unknownScopes))
+		fmt.Print(formatCommitReport(unknownScopes))
+	}
+
+	problems := len(nonConventional) + len(unknownScopes)
+	if problems == 0 {
+		fmt.Println("✅ All commits follow the conventions")
+		return nil
+	}
+
+	return fmt.Errorf("found %d commits that do not follow the conventions", problems)
+}
+
+// formatCommitReport lists commits as "  <short hash> <subject>" lines
+func formatCommitReport(conventionalCommits []*commits.ConventionalCommit) string {
+	var builder strings.Builder
+	for _, commit := range conventionalCommits {
+		hash := commit.Original.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		builder.WriteString(fmt.Sprintf("  %s %s\n", hash, commit.Original.Subject))
+	}
+	return builder.String()
+}
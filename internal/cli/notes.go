@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"herald/internal/changelog"
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/git"
+	"herald/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	notesOutput        string
+	notesFile          string
+	notesFromChangelog bool
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes [version]",
+	Short: "Print the release notes for a single version",
+	Long: `Print the release notes for a single version.
+
+Without a version, the notes for the upcoming release are computed from the
+commits since the latest tag. With a version, the notes are computed from the
+commits between that tag and the previous one, or read from the changelog
+file when --from-changelog is set or the tag does not exist.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		requested := ""
+		if len(args) > 0 {
+			requested = args[0]
+		}
+		return executeNotes(cfg, requested)
+	},
+}
+
+func init() {
+	notesCmd.Flags().StringVarP(&notesOutput, "output", "o", changelog.FormatMarkdown, "output format: markdown, text, or json")
+	notesCmd.Flags().StringVarP(&notesFile, "file", "f", "", "write notes to a file instead of stdout")
+	notesCmd.Flags().BoolVar(&notesFromChangelog, "from-changelog", false, "extract the notes from the changelog file")
+	rootCmd.AddCommand(notesCmd)
+}
+
+// executeNotes prints the notes for the requested version, or the upcoming release
+func executeNotes(cfg *config.Config, requested string) error {
+	section, err := findNotes(cfg, requested)
+	if err != nil {
+		return err
+	}
+
+	output, err := changelog.RenderNotes(section, notesOutput)
+	if err != nil {
+		return err
+	}
+
+	if notesFile == "" {
+		fmt.Print(output)
+		return nil
+	}
+
+	if err := os.WriteFile(notesFile, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	return nil
+}
+
+// findNotes locates the release section for the requested version
+func findNotes(cfg *config.Config, requested string) (*changelog.Section, error) {
+	if notesFromChangelog {
+		return notesFromChangelogFile(changelog.NewGenerator(cfg), requested)
+	}
+
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	changelogGenerator := changelog.NewGenerator(cfg)
+
+	if requested == "" {
+		release, err := upcomingRelease(cfg, repo)
+		if err != nil {
+			return nil, err
+		}
+		return changelogGenerator.ToSection(release), nil
+	}
+
+	release, err := taggedRelease(cfg, repo, requested)
+	if err != nil {
+		return nil, err
+	}
+	if release != nil {
+		return changelogGenerator.ToSection(release), nil
+	}
+
+	// No tag for this version, fall back to the changelog file
+	return notesFromChangelogFile(changelogGenerator, requested)
+}
+
+// taggedRelease builds the release for an existing version tag from the commits
+// since the previous version tag. It returns nil when the version is not tagged,
+// including names that are not versions, such as "Unreleased".
+func taggedRelease(cfg *config.Config, repo *git.Repository, requested string) (*changelog.Release, error) {
+	versionManager := version.NewManager(cfg)
+	requestedVersion, err := versionManager.ParseVersion(requested)
+	if err != nil {
+		return nil, nil
+	}
+
+	versionTags, err := getVersionTags(repo, versionManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	previousTag := ""
+	for _, vt := range versionTags {
+		if vt.Version.Compare(requestedVersion) != 0 {
+			previousTag = vt.Tag.Name
+			continue
+		}
+
+		gitCommits, err := repo.GetCommitsBetween(previousTag, vt.Tag.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits for %s: %w", vt.Tag.Name, err)
+		}
+
+		parser := commits.NewParser(cfg)
+		conventionalCommits, err := parseHistoryCommits(repo, parser, gitCommits)
+		if err != nil {
+			return nil, err
+		}
+
+		changelogGenerator := changelog.NewGenerator(cfg)
+		release := changelogGenerator.GenerateRelease(vt.Version, conventionalCommits)
+		release.Date = vt.Tag.Date
+		release.PreviousTag = previousTag
+		if err := markFirstTimeContributors(repo, changelogGenerator, release); err != nil {
+			return nil, err
+		}
+		return release, nil
+	}
+
+	return nil, nil
+}
+
+// notesFromChangelogFile extracts a version's section from the changelog file
+func notesFromChangelogFile(changelogGenerator *changelog.Generator, requested string) (*changelog.Section, error) {
+	content, err := changelogGenerator.ReadExistingChangelog()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := changelog.ParseDocument(content)
+	if requested == "" {
+		if doc.Unreleased != nil {
+			return doc.Unreleased, nil
+		}
+		if len(doc.Sections) == 0 {
+			return nil, fmt.Errorf("changelog has no release sections")
+		}
+		return doc.Sections[0], nil
+	}
+
+	section := doc.Release(requested)
+	if section == nil {
+		return nil, fmt.Errorf("version %s not found in tags or changelog", requested)
+	}
+	return section, nil
+}
+
+// upcomingRelease builds the release for the commits since the latest tag
+func upcomingRelease(cfg *config.Config, repo *git.Repository) (*changelog.Release, error) {
+	// Get latest tag
+	latestTag, err := repo.GetLatestTag()
+	if err != nil {
+		latestTag = nil
+	}
+
+	// Get current version
+	versionManager := version.NewManager(cfg)
+	var currentVersion *version.Version
+	if latestTag != nil {
+		currentVersion, err = versionManager.GetCurrentVersion(latestTag.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse current version: %w", err)
+		}
+	} else {
+		currentVersion, err = versionManager.GetInitialVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get initial version: %w", err)
+		}
+	}
+
+	// Get commits since last tag
+	var gitCommits []*git.Commit
+	if latestTag != nil {
+		gitCommits, err = repo.GetCommitsSinceTag(latestTag.Name)
+	} else {
+		gitCommits, err = repo.GetAllCommits()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	if len(gitCommits) == 0 {
+		return nil, fmt.Errorf("no new commits since last release")
+	}
+
+	// Parse conventional commits
+	parser := commits.NewParser(cfg)
+	conventionalCommits, err := parseCommits(repo, parser, gitCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	bumpType := parser.CalculateBumpType(conventionalCommits)
+	nextVersion := versionManager.CalculateNextVersion(currentVersion, bumpType)
+
+	changelogGenerator := changelog.NewGenerator(cfg)
+	release := changelogGenerator.GenerateRelease(nextVersion, conventionalCommits)
+	if latestTag != nil {
+		release.PreviousTag = latestTag.Name
+	}
+	if err := markFirstTimeContributors(repo, changelogGenerator, release); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"herald/internal/changelog"
+	"herald/internal/config"
+	"herald/internal/publish"
+
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify [version]",
+	Short: "Send the release notifications for a version",
+	Long: `Send the release notifications configured under notify.targets.
+
+Without a version, the notifications for the latest tag are sent.
+"herald release" sends them when the tag is on the remote; use this command
+after pushing the tag, or to send them again after a failure. With --dry-run
+the payloads are printed instead.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		requested := ""
+		if len(args) > 0 {
+			requested = args[0]
+		}
+		return executeNotify(cfg, requested, dryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+}
+
+// executeNotify sends the notifications for the requested release
+func executeNotify(cfg *config.Config, requested string, dryRun bool) error {
+	if len(cfg.Notify.Targets) == 0 {
+		return fmt.Errorf("no notification targets configured under notify.targets")
+	}
+
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	release, err := requestedRelease(cfg, repo, requested)
+	if err != nil {
+		return err
+	}
+
+	// The notification publisher builds the same message as "herald release" sends
+	section, err := releaseNotes(changelog.NewGenerator(cfg), release)
+	if err != nil {
+		return err
+	}
+	published, err := publish.NewRelease(cfg, release, section, "")
+	if err != nil {
+		return err
+	}
+	notifier := publish.NewNotifier(cfg)
+
+	if dryRun {
+		description, err := notifier.DryRun(published)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%s", description)
+		return nil
+	}
+
+	if _, err := notifier.Publish(context.Background(), published); err != nil {
+		return err
+	}
+	fmt.Printf("Notified %d targets\n", len(cfg.Notify.Targets))
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"herald/internal/changelog"
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/plugin"
+	"herald/internal/version"
+)
+
+// releasePlugins invokes the plugins at the lifecycle points of a release
+type releasePlugins struct {
+	runner          *plugin.Runner
+	previousVersion string
+	bump            commits.BumpType
+	commits         []*commits.ConventionalCommit
+	dryRun          bool
+}
+
+// newReleasePlugins prepares the plugin invocations of a release
+func newReleasePlugins(cfg *config.Config, previousVersion string, bump commits.BumpType, conventionalCommits []*commits.ConventionalCommit, dryRun bool) *releasePlugins {
+	return &releasePlugins{
+		runner:          plugin.NewRunner(cfg),
+		previousVersion: previousVersion,
+		bump:            bump,
+		commits:         conventionalCommits,
+		dryRun:          dryRun,
+	}
+}
+
+// version invokes the version plugins and returns the version to release and the notes they added
+func (p *releasePlugins) version(versionManager *version.Manager, currentVersion, nextVersion *version.Version) (*version.Version, []string, error) {
+	request := plugin.NewRequest(plugin.EventVersion, p.previousVersion, nextVersion.String(), p.bump, p.commits, p.dryRun)
+	outcome, err := p.runner.Run(context.Background(), request)
+	if err != nil {
+		return nil, nil, err
+	}
+	if outcome.Version == "" {
+		return nextVersion, outcome.Notes, nil
+	}
+
+	pluginVersion, err := versionManager.ParseVersion(outcome.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("plugins set an invalid version: %w", err)
+	}
+	if p.previousVersion != "" && pluginVersion.Compare(currentVersion) <= 0 {
+		return nil, nil, fmt.Errorf("plugins set version %s, which is not greater than the current version %s", pluginVersion.String(), currentVersion.String())
+	}
+
+	// Later events and hooks see the bump of the version the plugins chose
+	p.bump = version.BumpBetween(currentVersion, pluginVersion)
+	fmt.Printf("Version changed by plugins: %s (bump type: %s)\n", pluginVersion.String(), p.bump.String())
+	return pluginVersion, outcome.Notes, nil
+}
+
+// notes invokes the notes plugins and adds their notes to the release
+func (p *releasePlugins) notes(changelogGenerator *changelog.Generator, release *changelog.Release) error {
+	request := plugin.NewRequest(plugin.EventNotes, p.previousVersion, release.Version.String(), p.bump, p.commits, p.dryRun)
+	request.Notes = changelogGenerator.ToSection(release).Body()
+
+	outcome, err := p.runner.Run(context.Background(), request)
+	if err != nil {
+		return err
+	}
+	release.Notes = append(release.Notes, outcome.Notes...)
+	return nil
+}
+
+// released invokes the released plugins, whose responses are ignored
+func (p *releasePlugins) released(changelogGenerator *changelog.Generator, release *changelog.Release) error {
+	request := plugin.NewRequest(plugin.EventReleased, p.previousVersion, release.Version.String(), p.bump, p.commits, p.dryRun)
+	request.Notes = changelogGenerator.ToSection(release).Body()
+
+	_, err := p.runner.Run(context.Background(), request)
+	return err
+}
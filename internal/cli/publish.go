@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"herald/internal/changelog"
+	"herald/internal/config"
+	"herald/internal/git"
+	"herald/internal/publish"
+	"herald/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var publishRetry bool
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [version]",
+	Short: "Publish the release of a tagged version",
+	Long: `Publish the release of a tagged version with the enabled publishers:
+the GitHub, GitLab and Gitea releases under publish and the notifications
+under notify, in the order of publish.order. The notes are read from the
+version's section of the changelog file.
+
+Without a version, the latest tag is published. "herald release" publishes
+automatically when a hook pushed the tag; otherwise use this command after
+pushing the tag. The result of
+every publisher is recorded in the git directory, and --retry runs only the
+publishers that did not succeed. With --dry-run the release is only described.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(cfgFile)
+		if err != nil {
+			return err
+		}
+		requested := ""
+		if len(args) > 0 {
+			requested = args[0]
+		}
+		return executePublish(cfg, requested, dryRun, publishRetry)
+	},
+}
+
+func init() {
+	publishCmd.Flags().BoolVar(&publishRetry, "retry", false, "only run the publishers that failed or did not run")
+	rootCmd.AddCommand(publishCmd)
+}
+
+// executePublish publishes the release of the requested tag
+func executePublish(cfg *config.Config, requested string, dryRun, retry bool) error {
+	publishers, err := publish.Publishers(cfg)
+	if err != nil {
+		return err
+	}
+	if len(publishers) == 0 {
+		return fmt.Errorf("no publisher enabled, enable publish.github, publish.gitlab or publish.gitea, or add notify.targets")
+	}
+
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	release, err := requestedRelease(cfg, repo, requested)
+	if err != nil {
+		return err
+	}
+
+	return publishRelease(cfg, repo, changelog.NewGenerator(cfg), release, dryRun, retry)
+}
+
+// requestedRelease builds the release of a tagged version, or of the latest tag
+// when no version is requested
+func requestedRelease(cfg *config.Config, repo *git.Repository, requested string) (*changelog.Release, error) {
+	if requested == "" {
+		latestTag, err := repo.GetLatestTag()
+		if err != nil {
+			return nil, fmt.Errorf("no tagged release: %w", err)
+		}
+		requested = latestTag.Name
+	}
+
+	release, err := taggedRelease(cfg, repo, requested)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, fmt.Errorf("version %s is not tagged", requested)
+	}
+	return release, nil
+}
+
+// releaseNotes returns the notes of a release as written to the changelog file, so
+// publishing again sends the notes of "herald release", including plugin notes and
+// notes moved in from Unreleased. Releases missing from the file use the notes
+// generated from the commits.
+func releaseNotes(changelogGenerator *changelog.Generator, release *changelog.Release) (*changelog.Section, error) {
+	content, err := changelogGenerator.ReadExistingChangelog()
+	if err != nil {
+		return nil, err
+	}
+	if section := changelog.ParseDocument(content).Release(release.Version.String()); section != nil {
+		return section, nil
+	}
+	return changelogGenerator.ToSection(release), nil
+}
+
+// autoPublish publishes a release right after it is tagged. Herald does not push,
+// so publishing is left to "herald publish" when no hook pushed the tag.
+func autoPublish(cfg *config.Config, repo *git.Repository, changelogGenerator *changelog.Generator, release *changelog.Release) error {
+	publishers, err := publish.Publishers(cfg)
+	if err != nil {
+		return err
+	}
+	if len(publishers) == 0 {
+		return nil
+	}
+
+	tagName := version.NewManager(cfg).FormatTagName(release.Version)
+	if commit, err := repo.GetRemoteRef(cfg.Publish.Remote, "refs/tags/"+tagName); err != nil || commit == "" {
+		fmt.Printf("\nTag %s is not on %s yet, push it and run 'herald publish %s' to publish the release\n", tagName, cfg.Publish.Remote, release.Version.String())
+		return nil
+	}
+
+	return publishRelease(cfg, repo, changelogGenerator, release, false, false)
+}
+
+// publishRelease runs the enabled publishers in order, or describes them in dry-run mode.
+// A failing publisher does not stop the others; the results are recorded for --retry.
+func publishRelease(cfg *config.Config, repo *git.Repository, changelogGenerator *changelog.Generator, release *changelog.Release, dryRun, retry bool) error {
+	publishers, err := publish.Publishers(cfg)
+	if err != nil {
+		return err
+	}
+	if len(publishers) == 0 {
+		return nil
+	}
+
+	// The tag does not exist yet in dry-run mode
+	tagName := version.NewManager(cfg).FormatTagName(release.Version)
+	commit := ""
+	if !dryRun {
+		commit, err = repo.ResolveCommit(tagName)
+		if err != nil {
+			return err
+		}
+	}
+	section, err := releaseNotes(changelogGenerator, release)
+	if err != nil {
+		return err
+	}
+	published, err := publish.NewRelease(cfg, release, section, commit)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		for _, publisher := range publishers {
+			description, err := publisher.DryRun(published)
+			if err != nil {
+				return fmt.Errorf("%s: %w", publisher.Name(), err)
+			}
+			fmt.Printf("\n%s", description)
+		}
+		return nil
+	}
+
+	gitDir, err := repo.GitDir()
+	if err != nil {
+		return err
+	}
+	statePath := publish.StatePath(gitDir, tagName)
+
+	state := publish.NewState(statePath, tagName)
+	if retry {
+		state, err = publish.LoadState(statePath)
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			return fmt.Errorf("no publish results recorded for %s, run 'herald publish %s' first", tagName, release.Version.String())
+		}
+	}
+
+	failed, ran := 0, 0
+	for _, publisher := range publishers {
+		if retry && state.Succeeded(publisher.Name()) {
+			fmt.Printf("Skipping %s, already published\n", publisher.Name())
+			continue
+		}
+
+		ran++
+		fmt.Printf("Publishing %s to %s\n", tagName, publisher.Name())
+		releaseURL, err := publisher.Publish(context.Background(), published)
+		state.Record(publisher.Name(), releaseURL, err)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "❌ Publishing to %s failed: %v\n", publisher.Name(), err)
+			continue
+		}
+		if releaseURL != "" {
+			fmt.Printf("Published %s\n", releaseURL)
+		}
+	}
+
+	if err := state.Save(); err != nil {
+		return err
+	}
+
+	if retry && ran == 0 {
+		fmt.Printf("All publishers already succeeded for %s\n", tagName)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d publishers failed: %s", failed, ran, strings.Join(state.Failed(), ", "))
+	}
+	return nil
+}
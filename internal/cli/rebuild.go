@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"herald/internal/changelog"
+	"herald/internal/commits"
+	"herald/internal/config"
+	"herald/internal/git"
+	"herald/internal/version"
+)
+
+// versionTag pairs a git tag with its parsed version
+type versionTag struct {
+	Tag     *git.Tag
+	Version *version.Version
+}
+
+// getVersionTags returns all tags that parse as semantic versions, oldest version first
+func getVersionTags(repo *git.Repository, versionManager *version.Manager) ([]*versionTag, error) {
+	tags, err := repo.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*versionTag
+	for _, tag := range tags {
+		ver, err := versionManager.ParseVersion(tag.Name)
+		if err != nil {
+			continue // Skip tags that are not versions
+		}
+		result = append(result, &versionTag{Tag: tag, Version: ver})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Version.Compare(result[j].Version) < 0
+	})
+
+	return result, nil
+}
+
+// executeChangelogRebuild regenerates the whole changelog from the tag history
+func executeChangelogRebuild(cfg *config.Config, dryRun bool) error {
+	// Open git repository
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	versionManager := version.NewManager(cfg)
+	versionTags, err := getVersionTags(repo, versionManager)
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	if len(versionTags) == 0 {
+		fmt.Println("No version tags found, nothing to rebuild")
+		return nil
+	}
+
+	parser := commits.NewParser(cfg)
+	changelogGenerator := changelog.NewGenerator(cfg)
+
+	// Build one release per tag, covering the commits since the previous tag
+	var releases []*changelog.Release
+	previousTag := ""
+	for _, vt := range versionTags {
+		gitCommits, err := repo.GetCommitsBetween(previousTag, vt.Tag.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get commits for %s: %w", vt.Tag.Name, err)
+		}
+
+		conventionalCommits, err := parseHistoryCommits(repo, parser, gitCommits)
+		if err != nil {
+			return err
+		}
+
+		release := changelogGenerator.GenerateRelease(vt.Version, conventionalCommits)
+		release.Date = vt.Tag.Date
+		release.PreviousTag = previousTag
+		if err := markFirstTimeContributors(repo, changelogGenerator, release); err != nil {
+			return err
+		}
+		releases = append(releases, release)
+
+		previousTag = vt.Tag.Name
+	}
+
+	// Newest release first
+	for i, j := 0, len(releases)-1; i < j; i, j = i+1, j-1 {
+		releases[i], releases[j] = releases[j], releases[i]
+	}
+
+	if dryRun {
+		fmt.Print(changelogGenerator.FormatFullChangelog(releases))
+		return nil
+	}
+
+	fmt.Printf("Rebuilding changelog from %d tags: %s\n", len(releases), cfg.Changelog.File)
+	if err := changelogGenerator.GenerateFullChangelog(releases); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+
+	fmt.Println("✅ Changelog rebuilt successfully!")
+	return nil
+}
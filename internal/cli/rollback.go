@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"herald/internal/git"
+)
+
+// releaseRollback undoes the local changes of a release that failed before it was published
+type releaseRollback struct {
+	repo *git.Repository
+	head string // Commit the release started from, empty when there is nothing to undo
+	tag  string // Tag created by the release
+}
+
+// newReleaseRollback remembers the commit a release starts from. Dry runs change nothing
+// and get a rollback that does nothing.
+func newReleaseRollback(repo *git.Repository, dryRun bool) (*releaseRollback, error) {
+	rollback := &releaseRollback{repo: repo}
+	if dryRun {
+		return rollback, nil
+	}
+
+	head, err := repo.ResolveCommit("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	rollback.head = head
+	return rollback, nil
+}
+
+// restore deletes the release tag and resets the repository to the starting commit,
+// discarding the changelog update and files changed by hooks.
+// The working directory was clean when the release started.
+func (r *releaseRollback) restore(cause error) error {
+	if r.head == "" || !r.changed() {
+		return cause
+	}
+
+	fmt.Fprintf(os.Stderr, "Release failed, rolling back: %v\n", cause)
+	if r.tag != "" {
+		if err := r.repo.DeleteTag(r.tag); err != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+		}
+	}
+	if err := r.repo.Restore(r.head); err != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+
+	return fmt.Errorf("%w (release rolled back)", cause)
+}
+
+// changed reports whether the release left a tag, a commit or changed files behind
+func (r *releaseRollback) changed() bool {
+	if r.tag != "" {
+		return true
+	}
+	head, err := r.repo.ResolveCommit("HEAD")
+	if err != nil || head != r.head {
+		return true
+	}
+	isClean, err := r.repo.IsClean()
+	return err != nil || !isClean
+}
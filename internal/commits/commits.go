@@ -1,7 +1,9 @@
 package commits
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"herald/internal/config"
@@ -16,9 +18,19 @@ type ConventionalCommit struct {
 	Body             string
 	IsBreakingChange bool
 	BreakingChanges  []string
+	BreakingReason   string   // What marked the commit as breaking, e.g. `"!" in header`
+	References       []Reference // Issues referenced from the header and footers
+	IsRevert         bool
+	RevertedHash     string // Hash of the reverted commit, when the message names it
+	Ticket           string // Ticket key from the header, e.g. "PROJ-123", when the header pattern has one
+	Emoji            string // Leading gitmoji of the header, for the gitmoji preset
+	EmojiSemver      string // Semver level of the gitmoji, over the type's level, when its mapping sets one
 	Original         *git.Commit
 }
 
+// TypeOther is the type given to commits that do not follow the conventional format
+const TypeOther = "other"
+
 // BumpType represents the type of version bump needed
 type BumpType int
 
@@ -44,21 +56,42 @@ func (bt BumpType) String() string {
 
 // Parser handles parsing of conventional commits
 type Parser struct {
-	config *config.Config
-	regex  *regexp.Regexp
+	config   *config.Config
+	regex    *regexp.Regexp
+	gitmoji  *gitmojiTable // Set when the gitmoji header preset is configured
+	trackers []*tracker
+}
+
+// header holds the fields matched by the header pattern
+type header struct {
+	Type          string
+	Scope         string
+	Breaking      bool
+	Description   string
+	Ticket        string
+	Emoji         string // Leading gitmoji, for the gitmoji preset
+	BreakingEmoji bool   // The gitmoji itself marks a breaking change
+	EmojiSemver   string // Semver level set by the gitmoji mapping
 }
 
 // NewParser creates a new conventional commits parser
 func NewParser(cfg *config.Config) *Parser {
-	// Conventional commit regex pattern
-	// Matches: type(scope): description
-	pattern := `^(\w+)(?:\(([^)]+)\))?: (.+)$`
-	regex := regexp.MustCompile(pattern)
+	// The header pattern is validated when the config is loaded, fall back to
+	// the conventional grammar for configs that were not
+	regex, err := cfg.Commits.HeaderRegexp()
+	if err != nil {
+		regex = regexp.MustCompile(config.HeaderPresets["conventional"])
+	}
 
-	return &Parser{
-		config: cfg,
-		regex:  regex,
+	parser := &Parser{
+		config:   cfg,
+		regex:    regex,
+		trackers: compileTrackers(cfg.Repository),
 	}
+	if strings.EqualFold(cfg.Commits.HeaderPattern, "gitmoji") {
+		parser.gitmoji = newGitmojiTable(cfg.Commits.Gitmoji)
+	}
+	return parser
 }
 
 // ParseCommit parses a single git commit into a conventional commit
@@ -69,24 +102,117 @@ func (p *Parser) ParseCommit(commit *git.Commit) (*ConventionalCommit, error) {
 	}
 
 	// Parse the commit subject line
-	matches := p.regex.FindStringSubmatch(commit.Subject)
-	if len(matches) != 4 {
+	parsed := p.parseSubject(commit.Subject)
+	if parsed == nil && commit.IsMerge() && p.MergeStrategy() == "parse" {
+		// Pull request merges carry the conventional message in the body
+		parsed = p.findHeaderInBody(commit.Body)
+	}
+	if parsed == nil {
 		// Not a conventional commit, treat as unknown type
-		cc.Type = "other"
+		cc.Type = TypeOther
 		cc.Description = commit.Subject
+		parsed = &header{}
 	} else {
-		cc.Type = matches[1]
-		cc.Scope = matches[2]
-		cc.Description = matches[3]
+		cc.Type = parsed.Type
+		cc.Scope = parsed.Scope
+		cc.Description = parsed.Description
+		cc.Ticket = parsed.Ticket
+		cc.Emoji = parsed.Emoji
+		cc.EmojiSemver = parsed.EmojiSemver
+	}
+
+	// Recognize git's default revert subject: Revert "feat: add X"
+	if cc.Type == TypeOther {
+		if revertMatches := gitRevertPattern.FindStringSubmatch(commit.Subject); revertMatches != nil {
+			cc.Type = TypeRevert
+			cc.Description = revertMatches[1]
+		}
 	}
+	if cc.Type == TypeRevert {
+		cc.IsRevert = true
+		cc.RevertedHash = extractRevertedHash(commit.Body)
+	}
+
+	// Map scope aliases to their canonical scope
+	cc.Scope = p.NormalizeScope(cc.Scope)
 
 	// Check for breaking changes
-	cc.IsBreakingChange = p.hasBreakingChange(commit)
+	cc.BreakingReason = p.breakingReason(commit, parsed)
+	cc.IsBreakingChange = cc.BreakingReason != ""
 	cc.BreakingChanges = p.extractBreakingChanges(commit)
+	cc.References = p.extractReferences(cc)
 
 	return cc, nil
 }
 
+// parseSubject parses a header line, with a leading gitmoji when the gitmoji
+// preset is configured, nil when it does not match
+func (p *Parser) parseSubject(line string) *header {
+	if p.gitmoji != nil {
+		if parsed := p.parseGitmojiHeader(line); parsed != nil {
+			return parsed
+		}
+	}
+	return p.parseHeader(line)
+}
+
+// parseHeader matches a header line against the header pattern, nil when it does not match
+func (p *Parser) parseHeader(line string) *header {
+	matches := p.regex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+
+	parsed := &header{}
+	for i, name := range p.regex.SubexpNames() {
+		switch name {
+		case "type":
+			parsed.Type = matches[i]
+		case "scope":
+			parsed.Scope = strings.TrimSpace(matches[i])
+		case "breaking":
+			parsed.Breaking = matches[i] != ""
+		case "description":
+			parsed.Description = strings.TrimSpace(matches[i])
+		case "ticket":
+			parsed.Ticket = matches[i]
+		}
+	}
+
+	if parsed.Type == "" {
+		return nil
+	}
+	return parsed
+}
+
+// findHeaderInBody returns the first conventional header line in a commit body
+func (p *Parser) findHeaderInBody(body string) *header {
+	for _, line := range strings.Split(body, "\n") {
+		if parsed := p.parseSubject(strings.TrimSpace(line)); parsed != nil {
+			return parsed
+		}
+	}
+	return nil
+}
+
+// HasConventionalHeader reports whether the commit subject, or for merge
+// commits the body, contains a conventional commit header
+func (p *Parser) HasConventionalHeader(commit *git.Commit) bool {
+	if p.parseSubject(commit.Subject) != nil {
+		return true
+	}
+	return commit.IsMerge() && p.findHeaderInBody(commit.Body) != nil
+}
+
+// MergeStrategy returns the configured merge commit strategy
+func (p *Parser) MergeStrategy() string {
+	strategy := strings.ToLower(p.config.Commits.MergeStrategy)
+	if strategy == "" {
+		return "include"
+	}
+	return strategy
+}
+
 // ParseCommits parses multiple git commits
 func (p *Parser) ParseCommits(commits []*git.Commit) ([]*ConventionalCommit, error) {
 	var result []*ConventionalCommit
@@ -102,6 +228,36 @@ func (p *Parser) ParseCommits(commits []*git.Commit) ([]*ConventionalCommit, err
 	return result, nil
 }
 
+// FilterNonConventional applies the non-conventional policy to parsed commits.
+// It returns the commits to keep and the non-conventional commits that were found;
+// those are kept only under the "include" policy.
+func (p *Parser) FilterNonConventional(commits []*ConventionalCommit) ([]*ConventionalCommit, []*ConventionalCommit) {
+	var kept, nonConventional []*ConventionalCommit
+	include := p.NonConventionalPolicy() == "include"
+
+	for _, commit := range commits {
+		if commit.Type != TypeOther {
+			kept = append(kept, commit)
+			continue
+		}
+		nonConventional = append(nonConventional, commit)
+		if include {
+			kept = append(kept, commit)
+		}
+	}
+
+	return kept, nonConventional
+}
+
+// NonConventionalPolicy returns the configured policy for non-conventional commits
+func (p *Parser) NonConventionalPolicy() string {
+	policy := strings.ToLower(p.config.Commits.NonConventional.Policy)
+	if policy == "" {
+		return "include"
+	}
+	return policy
+}
+
 // GroupCommitsByType groups conventional commits by their type
 func (p *Parser) GroupCommitsByType(commits []*ConventionalCommit) map[string][]*ConventionalCommit {
 	groups := make(map[string][]*ConventionalCommit)
@@ -124,17 +280,8 @@ func (p *Parser) FilterCommitsForChangelog(commits []*ConventionalCommit) []*Con
 			continue
 		}
 
-		// Otherwise, only include certain types
-		switch commit.Type {
-		case "feat", "fix":
-			result = append(result, commit)
-		case "docs", "style", "refactor", "test", "chore":
-			// Only include if it's a breaking change
-			if commit.IsBreakingChange {
-				result = append(result, commit)
-			}
-		default:
-			// Include unknown types
+		// Otherwise, only include visible types and breaking changes
+		if !p.config.Commits.IsTypeHidden(commit.Type) || commit.IsBreakingChange {
 			result = append(result, commit)
 		}
 	}
@@ -142,26 +289,10 @@ func (p *Parser) FilterCommitsForChangelog(commits []*ConventionalCommit) []*Con
 	return result
 }
 
-// CalculateBumpType determines the type of version bump needed based on commits
+// CalculateBumpType determines the type of version bump needed based on commits.
+// See ExplainBump for the decision behind each commit.
 func (p *Parser) CalculateBumpType(commits []*ConventionalCommit) BumpType {
-	bumpType := None
-
-	for _, commit := range commits {
-		// Breaking changes always require major bump
-		if commit.IsBreakingChange {
-			return Major
-		}
-
-		// Check configured semver bump for this commit type
-		if commitTypeConfig, exists := p.config.Commits.Types[commit.Type]; exists {
-			commitBump := parseBumpTypeFromString(commitTypeConfig.Semver)
-			if commitBump > bumpType {
-				bumpType = commitBump
-			}
-		}
-	}
-
-	return bumpType
+	return p.ExplainBump(commits).Bump
 }
 
 // parseBumpTypeFromString converts a string to BumpType
@@ -178,26 +309,77 @@ func parseBumpTypeFromString(bumpStr string) BumpType {
 	}
 }
 
-// hasBreakingChange checks if a commit contains breaking changes
-func (p *Parser) hasBreakingChange(commit *git.Commit) bool {
+// breakingReason returns what marks a commit as a breaking change, or an empty
+// string when it is not breaking
+func (p *Parser) breakingReason(commit *git.Commit, parsed *header) string {
+	if parsed.BreakingEmoji {
+		return "gitmoji " + parsed.Emoji
+	}
+
+	// "!" immediately before the colon of the header (feat!: or feat(scope)!:)
+	if parsed.Breaking {
+		return `"!" in header`
+	}
+
+	if p.BreakingDetection() == "lenient" {
+		return p.lenientBreakingReason(commit)
+	}
+
+	// Keywords only count as footer tokens ("BREAKING CHANGE: ...")
+	for _, line := range strings.Split(commit.Body, "\n") {
+		if keyword := p.footerKeyword(line); keyword != "" {
+			return fmt.Sprintf("footer %q", keyword)
+		}
+	}
+
+	return ""
+}
+
+// lenientBreakingReason treats "!:" anywhere in the subject and a keyword
+// anywhere in the message as breaking
+func (p *Parser) lenientBreakingReason(commit *git.Commit) string {
 	// Check for exclamation mark in subject (feat!: or feat(scope)!:)
 	if strings.Contains(commit.Subject, "!:") {
-		return true
+		return `"!" in header`
 	}
 
 	// Check for breaking change keywords in body
 	fullMessage := commit.Subject + "\n" + commit.Body
 	for _, keyword := range p.config.Commits.BreakingChangeKeywords {
 		if strings.Contains(fullMessage, keyword) {
-			return true
+			return fmt.Sprintf("keyword %q", keyword)
 		}
 	}
 
-	return false
+	return ""
+}
+
+// footerKeyword returns the breaking change keyword a footer line starts with,
+// followed by ": " or " #" as the footer token separator
+func (p *Parser) footerKeyword(line string) string {
+	for _, keyword := range p.config.Commits.BreakingChangeKeywords {
+		if strings.HasPrefix(line, keyword+": ") || strings.HasPrefix(line, keyword+" #") {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// BreakingDetection returns the configured breaking change detection mode
+func (p *Parser) BreakingDetection() string {
+	mode := strings.ToLower(p.config.Commits.BreakingDetection)
+	if mode == "" {
+		return "strict"
+	}
+	return mode
 }
 
 // extractBreakingChanges extracts breaking change descriptions from commit
 func (p *Parser) extractBreakingChanges(commit *git.Commit) []string {
+	if p.BreakingDetection() != "lenient" {
+		return p.extractBreakingFooters(commit)
+	}
+
 	var breakingChanges []string
 	fullMessage := commit.Subject + "\n" + commit.Body
 
@@ -226,6 +408,43 @@ func (p *Parser) extractBreakingChanges(commit *git.Commit) []string {
 	return breakingChanges
 }
 
+// extractBreakingFooters extracts the descriptions of breaking change footers
+func (p *Parser) extractBreakingFooters(commit *git.Commit) []string {
+	var breakingChanges []string
+
+	for _, line := range strings.Split(commit.Body, "\n") {
+		keyword := p.footerKeyword(line)
+		if keyword == "" {
+			continue
+		}
+		description := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, keyword), ":"))
+		if description != "" {
+			breakingChanges = append(breakingChanges, description)
+		}
+	}
+
+	return breakingChanges
+}
+
+// extractRefs collects the references listed in "Refs:" footers
+func extractRefs(body string) []string {
+	var refs []string
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < 5 || !strings.EqualFold(line[:5], "refs:") {
+			continue
+		}
+		for _, ref := range strings.FieldsFunc(line[5:], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
 // titleCase capitalizes the first letter of a string
 func titleCase(s string) string {
 	if s == "" {
@@ -239,6 +458,9 @@ func (p *Parser) GetCommitTypeTitle(commitType string) string {
 	if commitTypeConfig, exists := p.config.Commits.Types[commitType]; exists {
 		return commitTypeConfig.Title
 	}
+	if commitType == TypeOther && p.config.Commits.NonConventional.Section != "" {
+		return p.config.Commits.NonConventional.Section
+	}
 	// Fallback to capitalized type
 	return titleCase(commitType)
 }
@@ -262,33 +484,26 @@ func (p *Parser) GetBreakingChanges(commits []*ConventionalCommit) []*Convention
 	return result
 }
 
-// SortCommitsByType sorts commits by type priority for changelog display
+// SortCommitsByType sorts commits by type priority for changelog display.
+// Types with a configured order come first, the rest follow alphabetically.
 func (p *Parser) SortCommitsByType(groups map[string][]*ConventionalCommit) []string {
-	// Define priority order for commit types
-	priority := []string{"feat", "fix", "docs", "style", "refactor", "test", "chore"}
-	
 	var sortedTypes []string
-	
-	// Add types in priority order if they exist
-	for _, commitType := range priority {
-		if _, exists := groups[commitType]; exists {
-			sortedTypes = append(sortedTypes, commitType)
-		}
-	}
-	
-	// Add any remaining types
 	for commitType := range groups {
-		found := false
-		for _, existing := range sortedTypes {
-			if existing == commitType {
-				found = true
-				break
+		sortedTypes = append(sortedTypes, commitType)
+	}
+
+	sort.Slice(sortedTypes, func(i, j int) bool {
+		orderI := p.config.Commits.TypeOrder(sortedTypes[i])
+		orderJ := p.config.Commits.TypeOrder(sortedTypes[j])
+		if orderI != orderJ {
+			// Unordered types (0) sort after ordered ones
+			if orderI == 0 || orderJ == 0 {
+				return orderJ == 0
 			}
+			return orderI < orderJ
 		}
-		if !found {
-			sortedTypes = append(sortedTypes, commitType)
-		}
-	}
-	
+		return sortedTypes[i] < sortedTypes[j]
+	})
+
 	return sortedTypes
-} 
\ No newline at end of file
+}
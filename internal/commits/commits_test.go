@@ -0,0 +1,145 @@
+package commits
+
+import (
+	"slices"
+	"testing"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+func TestBreakingChangeDetection(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		strict  bool
+		lenient bool
+		footers []string // Breaking change descriptions found in strict mode
+	}{
+		{
+			name:    "keyword in the subject of a docs commit",
+			subject: "docs: explain BREAKING CHANGE policy",
+			strict:  false,
+			lenient: true,
+		},
+		{
+			name:    "bang before the colon",
+			subject: "feat!: drop the v1 API",
+			strict:  true,
+			lenient: true,
+		},
+		{
+			name:    "bang after a scope",
+			subject: "feat(api)!: drop the v1 API",
+			strict:  true,
+			lenient: true,
+		},
+		{
+			name:    "BREAKING CHANGE footer",
+			subject: "feat: new config format",
+			body:    "Configuration moved to YAML.\n\nBREAKING CHANGE: the JSON config is no longer read",
+			strict:  true,
+			lenient: true,
+			footers: []string{"the JSON config is no longer read"},
+		},
+		{
+			name:    "BREAKING-CHANGE footer",
+			subject: "fix: tighten validation",
+			body:    "Rejects empty names.\n\nBREAKING-CHANGE: empty names are an error",
+			strict:  true,
+			lenient: true,
+			footers: []string{"empty names are an error"},
+		},
+		{
+			name:    "BREAKING-CHANGE mentioned inside a body paragraph",
+			subject: "fix: tighten validation",
+			body:    "This is not a BREAKING-CHANGE: existing names stay valid.",
+			strict:  false,
+			lenient: true,
+		},
+		{
+			name:    "plain feature",
+			subject: "feat: add export",
+			body:    "Adds a CSV export.",
+			strict:  false,
+			lenient: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit := &git.Commit{Hash: "abc1234", Subject: tt.subject, Body: tt.body}
+
+			for _, mode := range []struct {
+				name string
+				want bool
+			}{{"strict", tt.strict}, {"lenient", tt.lenient}} {
+				cfg := config.DefaultConfig()
+				cfg.Commits.BreakingDetection = mode.name
+
+				cc, err := NewParser(cfg).ParseCommit(commit)
+				if err != nil {
+					t.Fatalf("%s: ParseCommit() error = %v", mode.name, err)
+				}
+				if cc.IsBreakingChange != mode.want {
+					t.Errorf("%s: IsBreakingChange = %v, want %v (reason %q)", mode.name, cc.IsBreakingChange, mode.want, cc.BreakingReason)
+				}
+				if cc.IsBreakingChange == (cc.BreakingReason == "") {
+					t.Errorf("%s: BreakingReason = %q does not match IsBreakingChange = %v", mode.name, cc.BreakingReason, cc.IsBreakingChange)
+				}
+				if mode.name == "strict" && !slices.Equal(cc.BreakingChanges, tt.footers) {
+					t.Errorf("strict: BreakingChanges = %q, want %q", cc.BreakingChanges, tt.footers)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakingChangeBump(t *testing.T) {
+	cfg := config.DefaultConfig()
+	parser := NewParser(cfg)
+
+	commits, err := parser.ParseCommits([]*git.Commit{
+		{Hash: "abc1234", Subject: "docs: explain BREAKING CHANGE policy"},
+		{Hash: "def5678", Subject: "fix: handle empty input"},
+	})
+	if err != nil {
+		t.Fatalf("ParseCommits() error = %v", err)
+	}
+	if bump := parser.CalculateBumpType(commits); bump != Patch {
+		t.Errorf("CalculateBumpType() = %s, want %s", bump, Patch)
+	}
+}
+
+func TestGitmojiSemver(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Commits.HeaderPattern = "gitmoji"
+	cfg.Commits.Gitmoji.Map = map[string]config.GitmojiMapping{
+		"⚡️":     {Type: "fix", Semver: "minor"},
+		":memo:": {Type: "docs", Semver: "patch"},
+	}
+	parser := NewParser(cfg)
+
+	tests := []struct {
+		subject string
+		want    BumpType
+	}{
+		{"⚡️ faster startup", Minor},
+		{":memo: document the flags", Patch},
+		{"🐛 fix crash", Patch},
+		{"💥 remove the v1 API", Major},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			commits, err := parser.ParseCommits([]*git.Commit{{Hash: "abc1234", Subject: tt.subject}})
+			if err != nil {
+				t.Fatalf("ParseCommits() error = %v", err)
+			}
+			if bump := parser.CalculateBumpType(commits); bump != tt.want {
+				t.Errorf("CalculateBumpType() = %s, want %s", bump, tt.want)
+			}
+		})
+	}
+}
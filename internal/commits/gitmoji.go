@@ -0,0 +1,187 @@
+package commits
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"herald/internal/config"
+)
+
+// gitmoji is a gitmoji and the commit type it stands for
+type gitmoji struct {
+	Emoji    string
+	Code     string
+	Type     string
+	Semver   string // Semver level over the type's level, empty to follow the type
+	Breaking bool
+}
+
+// defaultGitmojis maps the common gitmojis to the default commit types. The
+// first entry for a type provides its emoji in changelog headings.
+var defaultGitmojis = []gitmoji{
+	{Emoji: "✨", Code: ":sparkles:", Type: "feat"},
+	{Emoji: "💥", Code: ":boom:", Type: "feat", Breaking: true},
+	{Emoji: "🌐", Code: ":globe_with_meridians:", Type: "feat"},
+	{Emoji: "♿️", Code: ":wheelchair:", Type: "feat"},
+	{Emoji: "🐛", Code: ":bug:", Type: "fix"},
+	{Emoji: "🚑️", Code: ":ambulance:", Type: "fix"},
+	{Emoji: "🔒️", Code: ":lock:", Type: "fix"},
+	{Emoji: "⚡️", Code: ":zap:", Type: "fix"},
+	{Emoji: "🩹", Code: ":adhesive_bandage:", Type: "fix"},
+	{Emoji: "📝", Code: ":memo:", Type: "docs"},
+	{Emoji: "🎨", Code: ":art:", Type: "style"},
+	{Emoji: "💄", Code: ":lipstick:", Type: "style"},
+	{Emoji: "♻️", Code: ":recycle:", Type: "refactor"},
+	{Emoji: "🔥", Code: ":fire:", Type: "refactor"},
+	{Emoji: "🚚", Code: ":truck:", Type: "refactor"},
+	{Emoji: "✅", Code: ":white_check_mark:", Type: "test"},
+	{Emoji: "🧪", Code: ":test_tube:", Type: "test"},
+	{Emoji: "🔧", Code: ":wrench:", Type: "chore"},
+	{Emoji: "🔨", Code: ":hammer:", Type: "chore"},
+	{Emoji: "⬆️", Code: ":arrow_up:", Type: "chore"},
+	{Emoji: "⬇️", Code: ":arrow_down:", Type: "chore"},
+	{Emoji: "📦️", Code: ":package:", Type: "chore"},
+	{Emoji: "👷", Code: ":construction_worker:", Type: "chore"},
+	{Emoji: "💚", Code: ":green_heart:", Type: "chore"},
+	{Emoji: "🚀", Code: ":rocket:", Type: "chore"},
+	{Emoji: "🔖", Code: ":bookmark:", Type: "chore"},
+	{Emoji: "⏪️", Code: ":rewind:", Type: "revert"},
+}
+
+var (
+	// shortcodePattern matches a leading :shortcode:
+	shortcodePattern = regexp.MustCompile(`^:[a-z0-9_+-]+:`)
+
+	// gitmojiScopePattern matches an optional "(scope): " after the emoji
+	gitmojiScopePattern = regexp.MustCompile(`^\(([^)]+)\):?\s*`)
+)
+
+// gitmojiTable resolves gitmojis to commit types
+type gitmojiTable struct {
+	byKey     map[string]gitmoji // Keyed by emoji without variation selectors and by shortcode
+	typeEmoji map[string]string  // Heading emoji per commit type
+}
+
+// newGitmojiTable builds the gitmoji table from the built-in map and the configured overrides
+func newGitmojiTable(cfg config.GitmojiConfig) *gitmojiTable {
+	table := &gitmojiTable{
+		byKey:     make(map[string]gitmoji),
+		typeEmoji: make(map[string]string),
+	}
+
+	for _, entry := range defaultGitmojis {
+		table.add(entry)
+	}
+
+	// Sorted so that the heading emoji of custom types is stable
+	keys := make([]string, 0, len(cfg.Map))
+	for key := range cfg.Map {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		mapping := cfg.Map[key]
+		entry := gitmoji{Type: mapping.Type, Semver: mapping.Semver, Breaking: mapping.Breaking}
+		if shortcodePattern.MatchString(key) {
+			entry.Code = key
+		} else {
+			entry.Emoji = key
+		}
+		table.add(entry)
+	}
+
+	return table
+}
+
+// add registers a gitmoji, replacing earlier entries with the same emoji or shortcode
+func (t *gitmojiTable) add(entry gitmoji) {
+	if entry.Emoji != "" {
+		t.byKey[stripVariationSelectors(entry.Emoji)] = entry
+		if _, exists := t.typeEmoji[entry.Type]; !exists && !entry.Breaking {
+			t.typeEmoji[entry.Type] = entry.Emoji
+		}
+	}
+	if entry.Code != "" {
+		t.byKey[entry.Code] = entry
+	}
+}
+
+// parse reads a leading gitmoji from a header line. It returns the gitmoji and
+// the rest of the line, or nil when the line does not start with a known gitmoji.
+func (t *gitmojiTable) parse(line string) (*gitmoji, string) {
+	if code := shortcodePattern.FindString(line); code != "" {
+		if entry, exists := t.byKey[code]; exists {
+			return &entry, strings.TrimSpace(line[len(code):])
+		}
+		return nil, line
+	}
+
+	// Emoji are one or more code points, try the longest prefix first
+	runes := []rune(line)
+	for length := min(len(runes), 4); length > 0; length-- {
+		prefix := string(runes[:length])
+		if entry, exists := t.byKey[stripVariationSelectors(prefix)]; exists {
+			if entry.Emoji == "" {
+				entry.Emoji = prefix
+			}
+			return &entry, strings.TrimSpace(string(runes[length:]))
+		}
+	}
+
+	return nil, line
+}
+
+// stripVariationSelectors removes emoji variation selectors, which commit
+// messages use inconsistently
+func stripVariationSelectors(emoji string) string {
+	return strings.ReplaceAll(emoji, "\uFE0F", "")
+}
+
+// parseGitmojiHeader parses a gitmoji header line such as "✨ add login",
+// ":bug: (auth): fix crash" or "✨ feat(auth): add login"
+func (p *Parser) parseGitmojiHeader(line string) *header {
+	entry, rest := p.gitmoji.parse(line)
+	if entry == nil || rest == "" {
+		return nil
+	}
+
+	// A conventional header after the emoji takes precedence
+	if parsed := p.parseHeader(rest); parsed != nil {
+		parsed.BreakingEmoji = entry.Breaking && !parsed.Breaking
+		parsed.Breaking = parsed.Breaking || entry.Breaking
+		parsed.Emoji = entry.Emoji
+		parsed.EmojiSemver = entry.Semver
+		return parsed
+	}
+
+	parsed := &header{
+		Type:          entry.Type,
+		Breaking:      entry.Breaking,
+		BreakingEmoji: entry.Breaking,
+		Emoji:         entry.Emoji,
+		EmojiSemver:   entry.Semver,
+	}
+	if matches := gitmojiScopePattern.FindStringSubmatch(rest); matches != nil {
+		parsed.Scope = strings.TrimSpace(matches[1])
+		rest = rest[len(matches[0]):]
+	}
+	parsed.Description = strings.TrimSpace(rest)
+	if parsed.Description == "" {
+		return nil
+	}
+	return parsed
+}
+
+// GetCommitTypeHeading returns the changelog heading for a commit type, with
+// its gitmoji when emoji headings are enabled
+func (p *Parser) GetCommitTypeHeading(commitType string) string {
+	title := p.GetCommitTypeTitle(commitType)
+	if p.gitmoji == nil || !p.config.Commits.Gitmoji.EmojiHeadings {
+		return title
+	}
+	if emoji, exists := p.gitmoji.typeEmoji[commitType]; exists {
+		return emoji + " " + title
+	}
+	return title
+}
@@ -0,0 +1,121 @@
+package commits
+
+import (
+	"regexp"
+	"strings"
+
+	"herald/internal/config"
+)
+
+// Reference is an issue or ticket referenced by a commit
+type Reference struct {
+	Tracker string // Name of the matching tracker, empty when no tracker matched
+	ID      string // Issue key, e.g. "123" or "PROJ-123"
+	Text    string // Reference as written, e.g. "#123"
+	Action  string // "mentions" for the header, otherwise the footer token, e.g. "refs" or "closes"
+}
+
+// ActionMentions is the action of references found in the commit header
+const ActionMentions = "mentions"
+
+// referenceFooterPattern matches footers listing issues: "Refs: #1, #2", "Closes: PROJ-3" or "Fixes #4"
+var referenceFooterPattern = regexp.MustCompile(`(?i)^(refs|closes|close|fixes|fix|resolves|resolve)(?::\s*(\S.*)|\s+(#\S.*))$`)
+
+// issueValuePattern matches footer values shaped like an issue: "#123", "PROJ-123" or a URL
+var issueValuePattern = regexp.MustCompile(`^(?:#\d+|[A-Z][A-Z0-9]*-\d+|https?://\S+)$`)
+
+// tracker is a compiled issue tracker
+type tracker struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// compileTrackers compiles the configured trackers, leaving out invalid ones
+// (the config is validated at load)
+func compileTrackers(cfg config.RepositoryConfig) []*tracker {
+	var trackers []*tracker
+	for _, trackerConfig := range cfg.IssueTrackers() {
+		regex, err := trackerConfig.Regexp()
+		if err != nil {
+			continue
+		}
+		trackers = append(trackers, &tracker{name: trackerConfig.Name, regex: regex})
+	}
+	return trackers
+}
+
+// extractReferences collects the issue references of a commit from the header
+// ticket, the header description and the reference footers
+func (p *Parser) extractReferences(cc *ConventionalCommit) []Reference {
+	var references []Reference
+	seen := make(map[string]int)
+	add := func(reference Reference) {
+		key := reference.Tracker + "\x00" + reference.ID
+		if i, exists := seen[key]; exists {
+			// A footer says more than a mention in the header
+			if references[i].Action == ActionMentions {
+				references[i].Action = reference.Action
+			}
+			return
+		}
+		seen[key] = len(references)
+		references = append(references, reference)
+	}
+
+	if cc.Ticket != "" {
+		if matched := p.matchReferences(cc.Ticket, "refs"); len(matched) > 0 {
+			add(matched[0])
+		} else {
+			add(Reference{ID: cc.Ticket, Text: cc.Ticket, Action: "refs"})
+		}
+	}
+
+	for _, reference := range p.matchReferences(cc.Description, ActionMentions) {
+		add(reference)
+	}
+
+	for _, line := range strings.Split(cc.Body, "\n") {
+		matches := referenceFooterPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		action := strings.ToLower(matches[1])
+		for _, value := range strings.FieldsFunc(matches[2]+matches[3], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			matched := p.matchReferences(value, action)
+			if len(matched) == 0 {
+				// "Refs:" keeps issues no tracker knows, but not commit hashes
+				if action == "refs" && issueValuePattern.MatchString(value) {
+					add(Reference{ID: value, Text: value, Action: action})
+				}
+				continue
+			}
+			for _, reference := range matched {
+				add(reference)
+			}
+		}
+	}
+
+	return references
+}
+
+// matchReferences finds the references of every tracker in a text
+func (p *Parser) matchReferences(text, action string) []Reference {
+	var references []Reference
+	for _, tracker := range p.trackers {
+		for _, match := range tracker.regex.FindAllStringSubmatchIndex(text, -1) {
+			reference := Reference{
+				Tracker: tracker.name,
+				Text:    text[match[0]:match[1]],
+				Action:  action,
+			}
+			reference.ID = reference.Text
+			if idIndex := tracker.regex.SubexpIndex("id"); idIndex >= 0 && match[2*idIndex] >= 0 {
+				reference.ID = text[match[2*idIndex]:match[2*idIndex+1]]
+			}
+			references = append(references, reference)
+		}
+	}
+	return references
+}
@@ -0,0 +1,41 @@
+package commits
+
+import (
+	"slices"
+	"testing"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+func TestRefsFooterValues(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"Refs: #12", []string{"#12"}},
+		{"Refs: PROJ-34", []string{"PROJ-34"}},
+		{"Refs: https://tracker.example.com/issues/56", []string{"https://tracker.example.com/issues/56"}},
+		{"Refs: 1a2b3c4d", nil},
+		{"Refs: 1a2b3c4d, #12", []string{"#12"}},
+		{"Refs: later", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			parser := NewParser(config.DefaultConfig())
+			cc, err := parser.ParseCommit(&git.Commit{Hash: "abc1234", Subject: "fix: handle empty input", Body: tt.body})
+			if err != nil {
+				t.Fatalf("ParseCommit() error = %v", err)
+			}
+
+			var texts []string
+			for _, reference := range cc.References {
+				texts = append(texts, reference.Text)
+			}
+			if !slices.Equal(texts, tt.want) {
+				t.Errorf("References = %q, want %q", texts, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package commits
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TypeRevert is the type of commits that revert an earlier commit
+const TypeRevert = "revert"
+
+var (
+	// gitRevertPattern matches the subject written by "git revert"
+	gitRevertPattern = regexp.MustCompile(`^Revert "(.+)"$`)
+
+	// revertedHashPattern matches the body line written by "git revert"
+	revertedHashPattern = regexp.MustCompile(`(?i)this reverts commit ([0-9a-f]{7,40})`)
+
+	// hashPattern matches an abbreviated or full commit hash
+	hashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
+// RevertPair is a commit and the commit that reverted it within the same release
+type RevertPair struct {
+	Original *ConventionalCommit
+	Revert   *ConventionalCommit
+}
+
+// extractRevertedHash finds the hash of the reverted commit in a revert's body,
+// either from git's "This reverts commit <hash>" line or from a "Refs: <hash>" footer
+func extractRevertedHash(body string) string {
+	if matches := revertedHashPattern.FindStringSubmatch(body); matches != nil {
+		return matches[1]
+	}
+	for _, ref := range extractRefs(body) {
+		if hashPattern.MatchString(ref) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// CancelReverts removes reverts together with the commits they revert when both
+// are part of the given commits. Reverts are matched newest first, so a revert
+// of a revert restores the original change.
+func (p *Parser) CancelReverts(commits []*ConventionalCommit) ([]*ConventionalCommit, []*RevertPair) {
+	if !p.config.Commits.Reverts.Cancel {
+		return commits, nil
+	}
+
+	cancelled := make(map[*ConventionalCommit]bool)
+	var pairs []*RevertPair
+
+	for _, revert := range commits {
+		if !revert.IsRevert || cancelled[revert] {
+			continue
+		}
+		for _, original := range commits {
+			if original == revert || cancelled[original] || !reverts(revert, original) {
+				continue
+			}
+			cancelled[revert] = true
+			cancelled[original] = true
+			pairs = append(pairs, &RevertPair{Original: original, Revert: revert})
+			break
+		}
+	}
+
+	if len(pairs) == 0 {
+		return commits, nil
+	}
+
+	var kept []*ConventionalCommit
+	for _, commit := range commits {
+		if !cancelled[commit] {
+			kept = append(kept, commit)
+		}
+	}
+
+	return kept, pairs
+}
+
+// reverts reports whether revert undoes original
+func reverts(revert, original *ConventionalCommit) bool {
+	if original.Original == nil {
+		return false
+	}
+	if revert.RevertedHash != "" {
+		return strings.HasPrefix(original.Original.Hash, revert.RevertedHash)
+	}
+	// Without a hash, fall back to the quoted subject of the reverted commit
+	return revert.Description == original.Original.Subject
+}
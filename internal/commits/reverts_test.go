@@ -0,0 +1,111 @@
+package commits
+
+import (
+	"slices"
+	"testing"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+func TestCancelReverts(t *testing.T) {
+	tests := []struct {
+		name      string
+		disabled  bool
+		commits   []*git.Commit // Newest first, as git log lists them
+		kept      []string      // Hashes of the commits left after cancelling
+		cancelled [][2]string   // Original and revert hash of every cancelled pair
+	}{
+		{
+			name: "git revert names the reverted hash",
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add export"`, Body: "This reverts commit aaaaaaa1."},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+				{Hash: "ccccccc3", Subject: "fix: crash"},
+			},
+			kept:      []string{"ccccccc3"},
+			cancelled: [][2]string{{"aaaaaaa1", "bbbbbbb2"}},
+		},
+		{
+			name: "conventional revert with a Refs footer",
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: "revert: add export", Body: "Refs: aaaaaaa"},
+				{Hash: "aaaaaaa1", Subject: "feat: export everything"},
+			},
+			cancelled: [][2]string{{"aaaaaaa1", "bbbbbbb2"}},
+		},
+		{
+			name: "subject fallback without a hash",
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add export"`, Body: "It broke the build."},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+			},
+			cancelled: [][2]string{{"aaaaaaa1", "bbbbbbb2"}},
+		},
+		{
+			name: "subject fallback needs the exact subject",
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add exports"`},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+			},
+			kept: []string{"bbbbbbb2", "aaaaaaa1"},
+		},
+		{
+			name: "revert of a revert restores the original",
+			commits: []*git.Commit{
+				{Hash: "ccccccc3", Subject: `Revert "Revert "feat: add export""`, Body: "This reverts commit bbbbbbb2."},
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add export"`, Body: "This reverts commit aaaaaaa1."},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+			},
+			kept:      []string{"aaaaaaa1"},
+			cancelled: [][2]string{{"bbbbbbb2", "ccccccc3"}},
+		},
+		{
+			name: "reverted commit from an earlier release",
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add export"`, Body: "This reverts commit 9999999."},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+			},
+			kept: []string{"bbbbbbb2", "aaaaaaa1"},
+		},
+		{
+			name:     "cancelling disabled",
+			disabled: true,
+			commits: []*git.Commit{
+				{Hash: "bbbbbbb2", Subject: `Revert "feat: add export"`, Body: "This reverts commit aaaaaaa1."},
+				{Hash: "aaaaaaa1", Subject: "feat: add export"},
+			},
+			kept: []string{"bbbbbbb2", "aaaaaaa1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Commits.Reverts.Cancel = !tt.disabled
+			parser := NewParser(cfg)
+
+			commits, err := parser.ParseCommits(tt.commits)
+			if err != nil {
+				t.Fatalf("ParseCommits() error = %v", err)
+			}
+			kept, pairs := parser.CancelReverts(commits)
+
+			var keptHashes []string
+			for _, commit := range kept {
+				keptHashes = append(keptHashes, commit.Original.Hash)
+			}
+			if !slices.Equal(keptHashes, tt.kept) {
+				t.Errorf("kept = %v, want %v", keptHashes, tt.kept)
+			}
+
+			var cancelled [][2]string
+			for _, pair := range pairs {
+				cancelled = append(cancelled, [2]string{pair.Original.Original.Hash, pair.Revert.Original.Hash})
+			}
+			if !slices.Equal(cancelled, tt.cancelled) {
+				t.Errorf("cancelled = %v, want %v", cancelled, tt.cancelled)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package commits
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"herald/internal/config"
+)
+
+// BumpDecision records the version bump a single commit contributes and why
+type BumpDecision struct {
+	Commit   *ConventionalCommit
+	Bump     BumpType
+	Reason   string // Human readable explanation, e.g. "rule #1 (scope=examples)"
+	Rule     int    // 1-based index of the matching rule in commits.rules, 0 when no rule matched
+	Excluded bool   // A rule left the commit out of the bump calculation
+}
+
+// BumpExplanation is the outcome of the bump calculation for a set of commits
+type BumpExplanation struct {
+	Bump      BumpType
+	Decisions []*BumpDecision
+	Decisive  *BumpDecision // First commit contributing the final bump, nil when there is no bump
+	Reverted  []*RevertPair // Pairs cancelled out before the calculation
+}
+
+// ExplainBump calculates the version bump for the commits and records the
+// decision taken for every commit
+func (p *Parser) ExplainBump(commits []*ConventionalCommit) *BumpExplanation {
+	explanation := &BumpExplanation{Bump: None}
+
+	// Changes reverted within the same release do not count
+	commits, explanation.Reverted = p.CancelReverts(commits)
+
+	for _, commit := range commits {
+		decision := p.decideBump(commit)
+		explanation.Decisions = append(explanation.Decisions, decision)
+		if decision.Bump > explanation.Bump {
+			explanation.Bump = decision.Bump
+			explanation.Decisive = decision
+		}
+	}
+
+	return explanation
+}
+
+// decideBump determines the bump of a single commit. The first matching rule
+// wins; without one, breaking changes are major and other commits use the
+// semver level of their type.
+func (p *Parser) decideBump(commit *ConventionalCommit) *BumpDecision {
+	for i, rule := range p.config.Commits.Rules {
+		if !p.ruleMatches(rule, commit) {
+			continue
+		}
+		decision := &BumpDecision{
+			Commit: commit,
+			Rule:   i + 1,
+			Reason: fmt.Sprintf("rule #%d (%s)", i+1, describeRule(rule)),
+		}
+		if rule.Exclude {
+			decision.Excluded = true
+			decision.Reason += " excludes the commit"
+		} else {
+			decision.Bump = parseBumpTypeFromString(rule.Semver)
+		}
+		return decision
+	}
+
+	if commit.IsBreakingChange {
+		return &BumpDecision{Commit: commit, Bump: Major, Reason: "breaking change (" + commit.BreakingReason + ")"}
+	}
+
+	if commit.EmojiSemver != "" {
+		bump := parseBumpTypeFromString(commit.EmojiSemver)
+		return &BumpDecision{Commit: commit, Bump: bump, Reason: fmt.Sprintf("gitmoji %s bumps %s", commit.Emoji, bump)}
+	}
+
+	if commitTypeConfig, exists := p.config.Commits.Types[commit.Type]; exists {
+		bump := parseBumpTypeFromString(commitTypeConfig.Semver)
+		return &BumpDecision{Commit: commit, Bump: bump, Reason: fmt.Sprintf("type %q bumps %s", commit.Type, bump)}
+	}
+
+	return &BumpDecision{Commit: commit, Bump: None, Reason: fmt.Sprintf("type %q is not configured", commit.Type)}
+}
+
+// ruleMatches reports whether every matcher set on the rule matches the commit
+func (p *Parser) ruleMatches(rule config.BumpRule, commit *ConventionalCommit) bool {
+	if rule.Type != "" && !strings.EqualFold(rule.Type, commit.Type) {
+		return false
+	}
+	if rule.Scope != "" && p.NormalizeScope(rule.Scope) != commit.Scope {
+		return false
+	}
+	if rule.Breaking != nil && *rule.Breaking != commit.IsBreakingChange {
+		return false
+	}
+	if rule.Path != "" && !matchesAllFiles(rule.Path, commit) {
+		return false
+	}
+	return true
+}
+
+// matchesAllFiles reports whether every file changed by the commit matches the glob.
+// Commits without changed files never match a path rule.
+func matchesAllFiles(glob string, commit *ConventionalCommit) bool {
+	if commit.Original == nil || len(commit.Original.Files) == 0 {
+		return false
+	}
+
+	pattern := globToRegexp(glob)
+	for _, file := range commit.Original.Files {
+		if !pattern.MatchString(file) {
+			return false
+		}
+	}
+	return true
+}
+
+// globToRegexp converts a path glob to a regular expression. "*" and "?" stay
+// within a directory, "**" spans directories.
+func globToRegexp(glob string) *regexp.Regexp {
+	var builder strings.Builder
+	builder.WriteString("^")
+
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				// "dir/**/file" also matches "dir/file"
+				if i+1 < len(runes) && runes[i+1] == '/' {
+					i++
+					builder.WriteString("(?:.*/)?")
+				} else {
+					builder.WriteString(".*")
+				}
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+
+	builder.WriteString("$")
+	return regexp.MustCompile(builder.String())
+}
+
+// describeRule lists the matchers of a rule, e.g. "type=fix scope=api"
+func describeRule(rule config.BumpRule) string {
+	var matchers []string
+	if rule.Type != "" {
+		matchers = append(matchers, "type="+rule.Type)
+	}
+	if rule.Scope != "" {
+		matchers = append(matchers, "scope="+rule.Scope)
+	}
+	if rule.Path != "" {
+		matchers = append(matchers, "path="+rule.Path)
+	}
+	if rule.Breaking != nil {
+		matchers = append(matchers, fmt.Sprintf("breaking=%t", *rule.Breaking))
+	}
+	if len(matchers) == 0 {
+		return "any commit"
+	}
+	return strings.Join(matchers, " ")
+}
@@ -0,0 +1,107 @@
+package commits
+
+import (
+	"testing"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob  string
+		path  string
+		match bool
+	}{
+		{"docs/*", "docs/guide.md", true},
+		{"docs/*", "docs/api/index.md", false},
+		{"docs/**", "docs/api/index.md", true},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/api/index.md", true},
+		{"**/*.md", "docs/api/index.go", false},
+		{"examples/**/main.go", "examples/main.go", true},
+		{"examples/**/main.go", "examples/a/b/main.go", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"a.b", "axb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			if got := globToRegexp(tt.glob).MatchString(tt.path); got != tt.match {
+				t.Errorf("globToRegexp(%q) matches %q = %v, want %v", tt.glob, tt.path, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestBumpRules(t *testing.T) {
+	breaking := true
+	rules := []config.BumpRule{
+		{Path: "docs/**", Semver: "none"},
+		{Type: "feat", Scope: "examples", Semver: "patch"},
+		{Type: "chore", Scope: "deps", Semver: "patch"},
+		{Scope: "internal", Breaking: &breaking, Semver: "minor"},
+		{Type: "test", Exclude: true},
+	}
+
+	tests := []struct {
+		name     string
+		subject  string
+		files    []string
+		bump     BumpType
+		rule     int
+		excluded bool
+	}{
+		{name: "every file matches the path", subject: "feat: document export", files: []string{"docs/export.md", "docs/api/index.md"}, bump: None, rule: 1},
+		{name: "one file outside the path", subject: "feat: add export", files: []string{"docs/export.md", "export.go"}, bump: Minor},
+		{name: "no changed files never match a path", subject: "fix: typo", bump: Patch},
+		{name: "type and scope", subject: "feat(examples): add demo", bump: Patch, rule: 2},
+		{name: "type promoted by a rule", subject: "chore(deps): bump yaml", bump: Patch, rule: 3},
+		{name: "breaking internal change", subject: "refactor(internal)!: rename package", bump: Minor, rule: 4},
+		{name: "breaking change without a rule", subject: "feat(api)!: drop v1", bump: Major},
+		{name: "excluded commit", subject: "test: cover export", bump: None, rule: 5, excluded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Commits.Rules = rules
+			parser := NewParser(cfg)
+
+			commits, err := parser.ParseCommits([]*git.Commit{{Hash: "abc1234", Subject: tt.subject, Files: tt.files}})
+			if err != nil {
+				t.Fatalf("ParseCommits() error = %v", err)
+			}
+			explanation := parser.ExplainBump(commits)
+			decision := explanation.Decisions[0]
+
+			if decision.Bump != tt.bump {
+				t.Errorf("Bump = %s, want %s (%s)", decision.Bump, tt.bump, decision.Reason)
+			}
+			if decision.Rule != tt.rule {
+				t.Errorf("Rule = %d, want %d (%s)", decision.Rule, tt.rule, decision.Reason)
+			}
+			if decision.Excluded != tt.excluded {
+				t.Errorf("Excluded = %v, want %v", decision.Excluded, tt.excluded)
+			}
+		})
+	}
+}
+
+func TestFirstMatchingRuleWins(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Commits.Rules = []config.BumpRule{
+		{Scope: "api", Semver: "major"},
+		{Type: "fix", Scope: "api", Semver: "none"},
+	}
+	parser := NewParser(cfg)
+
+	commits, err := parser.ParseCommits([]*git.Commit{{Hash: "abc1234", Subject: "fix(api): handle nil"}})
+	if err != nil {
+		t.Fatalf("ParseCommits() error = %v", err)
+	}
+	if bump := parser.CalculateBumpType(commits); bump != Major {
+		t.Errorf("CalculateBumpType() = %s, want %s", bump, Major)
+	}
+}
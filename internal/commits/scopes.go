@@ -0,0 +1,71 @@
+package commits
+
+import (
+	"sort"
+	"strings"
+)
+
+// NormalizeScope maps a scope or one of its aliases to the configured scope name.
+// Unknown scopes are returned unchanged.
+func (p *Parser) NormalizeScope(scope string) string {
+	if scope == "" {
+		return scope
+	}
+	for name, scopeConfig := range p.config.Commits.Scopes {
+		if strings.EqualFold(name, scope) {
+			return name
+		}
+		for _, alias := range scopeConfig.Aliases {
+			if strings.EqualFold(alias, scope) {
+				return name
+			}
+		}
+	}
+	return scope
+}
+
+// GetScopeTitle returns the display name for a scope
+func (p *Parser) GetScopeTitle(scope string) string {
+	if scopeConfig, exists := p.config.Commits.Scopes[scope]; exists && scopeConfig.Title != "" {
+		return scopeConfig.Title
+	}
+	return scope
+}
+
+// IsAllowedScope reports whether a scope is permitted. Without restrict_scopes
+// every scope is allowed; commits without a scope are always allowed.
+func (p *Parser) IsAllowedScope(scope string) bool {
+	if scope == "" || !p.config.Commits.RestrictScopes {
+		return true
+	}
+	_, exists := p.config.Commits.Scopes[p.NormalizeScope(scope)]
+	return exists
+}
+
+// GroupCommitsByScope groups conventional commits by their scope
+func (p *Parser) GroupCommitsByScope(commits []*ConventionalCommit) map[string][]*ConventionalCommit {
+	groups := make(map[string][]*ConventionalCommit)
+
+	for _, commit := range commits {
+		groups[commit.Scope] = append(groups[commit.Scope], commit)
+	}
+
+	return groups
+}
+
+// SortScopes orders scopes by display name, with unscoped commits last
+func (p *Parser) SortScopes(groups map[string][]*ConventionalCommit) []string {
+	var scopes []string
+	for scope := range groups {
+		scopes = append(scopes, scope)
+	}
+
+	sort.Slice(scopes, func(i, j int) bool {
+		if scopes[i] == "" || scopes[j] == "" {
+			return scopes[j] == ""
+		}
+		return strings.ToLower(p.GetScopeTitle(scopes[i])) < strings.ToLower(p.GetScopeTitle(scopes[j]))
+	})
+
+	return scopes
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -11,10 +13,16 @@ import (
 
 // Config represents the Herald configuration
 type Config struct {
-	Version   VersionConfig   `yaml:"version"`
-	Commits   CommitsConfig   `yaml:"commits"`
-	Changelog ChangelogConfig `yaml:"changelog"`
-	Git       GitConfig       `yaml:"git"`
+	Version    VersionConfig    `yaml:"version"`
+	Commits    CommitsConfig    `yaml:"commits"`
+	Changelog  ChangelogConfig  `yaml:"changelog"`
+	Git        GitConfig        `yaml:"git"`
+	Repository RepositoryConfig `yaml:"repository"`
+	Notify     NotifyConfig     `yaml:"notify"`
+	Publish    PublishConfig    `yaml:"publish"`
+	Plugins    []PluginConfig   `yaml:"plugins"`
+	Hooks      HooksConfig      `yaml:"hooks"`
+	Lock       LockConfig       `yaml:"lock"`
 }
 
 // VersionConfig holds version-related settings
@@ -25,14 +33,165 @@ type VersionConfig struct {
 
 // CommitsConfig holds conventional commits settings
 type CommitsConfig struct {
-	Types                   map[string]CommitType `yaml:"types"`
-	BreakingChangeKeywords []string              `yaml:"breaking_change_keywords"`
+	Types                  map[string]CommitType  `yaml:"types"`
+	BreakingChangeKeywords []string               `yaml:"breaking_change_keywords"`
+	BreakingDetection      string                 `yaml:"breaking_detection"` // "strict", "lenient"
+	HeaderPattern          string                 `yaml:"header_pattern"`     // Preset name or regular expression with named groups
+	NonConventional        NonConventionalConfig  `yaml:"non_conventional"`
+	MergeStrategy          string                 `yaml:"merge_strategy"` // "include", "skip", "first-parent", "parse"
+	Reverts                RevertsConfig          `yaml:"reverts"`
+	Scopes                 map[string]ScopeConfig `yaml:"scopes"`
+	RestrictScopes         bool                   `yaml:"restrict_scopes"` // Only allow configured scopes and aliases
+	Rules                  []BumpRule             `yaml:"rules"`           // Bump overrides, the first matching rule wins
+	Gitmoji                GitmojiConfig          `yaml:"gitmoji"`         // Used by the "gitmoji" header preset
+}
+
+// GitmojiConfig controls the gitmoji header preset
+type GitmojiConfig struct {
+	EmojiHeadings bool                      `yaml:"emoji_headings"` // Prefix changelog section titles with the type's emoji
+	Map           map[string]GitmojiMapping `yaml:"map"`            // Emoji or :shortcode: to commit type, over the built-in map
+}
+
+// GitmojiMapping maps a gitmoji to a commit type and, optionally, a semver level
+type GitmojiMapping struct {
+	Type     string `yaml:"type"`
+	Semver   string `yaml:"semver"` // "major", "minor", "patch", "none"; the type's level when empty
+	Breaking bool   `yaml:"breaking"`
+}
+
+// BumpRule overrides the version bump of the commits it matches.
+// Empty matchers match any commit.
+type BumpRule struct {
+	Type     string `yaml:"type"`
+	Scope    string `yaml:"scope"`
+	Path     string `yaml:"path"`     // Glob that every file changed by the commit must match, "**" spans directories
+	Breaking *bool  `yaml:"breaking"` // Match only breaking (true) or non-breaking (false) commits
+	Semver   string `yaml:"semver"`   // "major", "minor", "patch", "none"
+	Exclude  bool   `yaml:"exclude"`  // Leave matching commits out of the bump calculation
+}
+
+// UsesPaths reports whether any bump rule matches on changed files
+func (c *CommitsConfig) UsesPaths() bool {
+	for _, rule := range c.Rules {
+		if rule.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderPresets are the built-in commit header grammars, selectable by name in header_pattern
+var HeaderPresets = map[string]string{
+	// Conventional Commits: type(scope)!: description
+	"conventional": `^(?P<type>[\w-]+)(?:\((?P<scope>[^)]+)\))?(?P<breaking>!)?: (?P<description>.+)$`,
+	// Angular: like conventional, restricted to the Angular commit types
+	"angular": `^(?P<type>build|ci|docs|feat|fix|perf|refactor|style|test|revert|chore)(?:\((?P<scope>[^)]+)\))?(?P<breaking>!)?: (?P<description>.+)$`,
+	// Gitmoji: "✨ add login" or ":bug: fix crash", conventional headers are accepted as well
+	"gitmoji": `^(?P<type>[\w-]+)(?:\((?P<scope>[^)]+)\))?(?P<breaking>!)?: (?P<description>.+)$`,
+	// Conventional headers with an optional ticket prefix: [PROJ-123] type(scope): description
+	"ticket": `^(?:\[(?P<ticket>[A-Z][A-Z0-9]*-\d+)\] )?(?P<type>[\w-]+)(?:\((?P<scope>[^)]+)\))?(?P<breaking>!)?: (?P<description>.+)$`,
+}
+
+// headerGroups are the named groups a header pattern may use
+var headerGroups = map[string]bool{
+	"type":        true,
+	"scope":       true,
+	"breaking":    true,
+	"description": true,
+	"ticket":      true,
+}
+
+// HeaderRegexp compiles the configured header pattern, resolving preset names.
+// The pattern must define the "type" and "description" groups and may define
+// "scope", "breaking" and "ticket".
+func (c *CommitsConfig) HeaderRegexp() (*regexp.Regexp, error) {
+	pattern := c.HeaderPattern
+	if pattern == "" {
+		pattern = "conventional"
+	}
+	if preset, exists := HeaderPresets[strings.ToLower(pattern)]; exists {
+		pattern = preset
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("commits.header_pattern is not a valid regular expression: %w", err)
+	}
+
+	found := make(map[string]bool)
+	for _, name := range regex.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		if !headerGroups[name] {
+			return nil, fmt.Errorf("commits.header_pattern has unknown group '%s' (must be: type, scope, breaking, description, or ticket)", name)
+		}
+		found[name] = true
+	}
+	for _, required := range []string{"type", "description"} {
+		if !found[required] {
+			return nil, fmt.Errorf("commits.header_pattern must define the '%s' group, e.g. (?P<%s>...)", required, required)
+		}
+	}
+
+	return regex, nil
+}
+
+// ScopeConfig defines the display name and aliases of a commit scope
+type ScopeConfig struct {
+	Title   string   `yaml:"title"`
+	Aliases []string `yaml:"aliases"`
+}
+
+// RevertsConfig controls how reverted commits are handled
+type RevertsConfig struct {
+	Cancel bool `yaml:"cancel"` // Drop a commit and its revert when both are in the same release
+	List   bool `yaml:"list"`   // List cancelled pairs in the changelog
+}
+
+// NonConventionalConfig controls commits whose subject does not follow the convention
+type NonConventionalConfig struct {
+	Policy  string `yaml:"policy"`  // "ignore", "include", "warn", "fail"
+	Section string `yaml:"section"` // Changelog section title when included
 }
 
 // CommitType defines a commit type with its display title and semver bump level
 type CommitType struct {
 	Title  string `yaml:"title"`
 	Semver string `yaml:"semver"` // "major", "minor", "patch", "none"
+	Order  int    `yaml:"order"`  // Position of the section in the changelog, lower first
+	Hidden *bool  `yaml:"hidden"` // Leave out of the changelog unless breaking or include_all is set
+}
+
+// legacyTypeOrder is the section order used for types that do not set an order
+var legacyTypeOrder = []string{"feat", "fix", "docs", "style", "refactor", "test", "chore"}
+
+// TypeOrder returns the changelog position of a commit type, 0 when unordered
+func (c *CommitsConfig) TypeOrder(commitType string) int {
+	if typeConfig, exists := c.Types[commitType]; exists && typeConfig.Order > 0 {
+		return typeConfig.Order
+	}
+	for i, legacyType := range legacyTypeOrder {
+		if legacyType == commitType {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// IsTypeHidden reports whether a commit type is left out of the changelog by default.
+// Types without an explicit setting keep the historical behaviour: the built-in
+// maintenance types are hidden, everything else is shown.
+func (c *CommitsConfig) IsTypeHidden(commitType string) bool {
+	if typeConfig, exists := c.Types[commitType]; exists && typeConfig.Hidden != nil {
+		return *typeConfig.Hidden
+	}
+	switch commitType {
+	case "docs", "style", "refactor", "test", "chore":
+		return true
+	default:
+		return false
+	}
 }
 
 // ChangelogConfig holds changelog generation settings
@@ -40,6 +199,20 @@ type ChangelogConfig struct {
 	File       string `yaml:"file"`
 	Template   string `yaml:"template"`
 	IncludeAll bool   `yaml:"include_all"`
+	Unreleased bool   `yaml:"unreleased"` // Keep a "## [Unreleased]" section at the top
+	GroupBy    string `yaml:"group_by"`   // "type", "scope", "type-then-scope"
+
+	Contributors ContributorsConfig `yaml:"contributors"`
+}
+
+// ContributorsConfig controls the contributors section of release notes
+type ContributorsConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	Handles       map[string]string `yaml:"handles"`      // Email to handle, e.g. "jane@example.com": "jane"
+	MappingFile   string            `yaml:"mapping_file"` // YAML file with additional email to handle entries
+	ExcludeBots   bool              `yaml:"exclude_bots"`
+	Bots          []string          `yaml:"bots"` // Name or email fragments identifying bots
+	MarkFirstTime bool              `yaml:"mark_first_time"`
 }
 
 // GitConfig holds git operation settings
@@ -49,7 +222,170 @@ type GitConfig struct {
 	CommitMessage   string `yaml:"commit_message"`
 }
 
+// RepositoryConfig holds the hosted repository settings used to render links
+type RepositoryConfig struct {
+	URL        string `yaml:"url"`         // Web URL, detected from the origin remote when empty
+	Provider   string `yaml:"provider"`    // "github", "gitlab", "gitea", "bitbucket"; detected from the URL when empty
+	CommitURL  string `yaml:"commit_url"`  // Template with {url}, {hash} and {short_hash}
+	CompareURL string `yaml:"compare_url"` // Template with {url}, {previous} and {current}
+	IssueURL   string `yaml:"issue_url"`   // Template with {url} and {id}
+
+	Trackers []TrackerConfig `yaml:"trackers"` // Issue trackers referenced from commits
+}
+
+// TrackerConfig defines how references to an issue tracker are recognized and linked
+type TrackerConfig struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"` // Regular expression, the "id" group (or the whole match) is the issue key
+	URL     string `yaml:"url"`     // Template with {id}; empty uses repository.issue_url
+}
+
+// RepositoryTracker is the built-in tracker for "#123" issue and pull request references
+const RepositoryTracker = "repository"
+
+// defaultTrackerPattern matches "#123" references
+const defaultTrackerPattern = `\B#(?P<id>\d+)\b`
+
+// IssueTrackers returns the configured trackers followed by the built-in
+// repository tracker, unless a tracker with that name is configured
+func (r *RepositoryConfig) IssueTrackers() []TrackerConfig {
+	trackers := append([]TrackerConfig{}, r.Trackers...)
+	for _, tracker := range trackers {
+		if tracker.Name == RepositoryTracker {
+			return trackers
+		}
+	}
+	return append(trackers, TrackerConfig{Name: RepositoryTracker, Pattern: defaultTrackerPattern})
+}
+
+// Regexp compiles the tracker pattern
+func (t TrackerConfig) Regexp() (*regexp.Regexp, error) {
+	pattern := t.Pattern
+	if pattern == "" && t.Name == RepositoryTracker {
+		pattern = defaultTrackerPattern
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("repository.trackers '%s' has an invalid pattern: %w", t.Name, err)
+	}
+	return regex, nil
+}
+
+// NotifyConfig holds the release notification settings
+type NotifyConfig struct {
+	Targets []NotifyTarget `yaml:"targets"`
+	Retries int            `yaml:"retries"` // Additional attempts after a failed post
+	Timeout int            `yaml:"timeout"` // Seconds per request
+}
+
+// NotifyTarget is a destination for release notifications
+type NotifyTarget struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`    // "slack", "discord", "teams", "webhook"
+	URL     string            `yaml:"url"`     // Webhook URL, ${VAR} references are expanded from the environment
+	Headers map[string]string `yaml:"headers"` // Extra request headers, values are expanded like the URL
+}
+
+// PublishConfig holds the release publishing targets
+type PublishConfig struct {
+	Order  []string            `yaml:"order"`  // Publisher names run first, in this order
+	Remote string              `yaml:"remote"` // Remote the release tag is pushed to
+	GitHub GitHubPublishConfig `yaml:"github"`
+	GitLab GitLabPublishConfig `yaml:"gitlab"`
+	Gitea  GiteaPublishConfig  `yaml:"gitea"`
+}
+
+// GitHubPublishConfig holds the settings for creating GitHub Releases
+type GitHubPublishConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	APIURL   string   `yaml:"api_url"`   // REST API base URL, "https://HOST/api/v3" for GitHub Enterprise Server
+	Owner    string   `yaml:"owner"`     // Detected from repository.url when empty
+	Repo     string   `yaml:"repo"`      // Detected from repository.url when empty
+	TokenEnv string   `yaml:"token_env"` // Environment variable holding the token
+	Assets   []string `yaml:"assets"`    // Glob patterns of files to upload
+	Draft    bool     `yaml:"draft"`
+}
+
+// GitLabPublishConfig holds the settings for creating GitLab releases
+type GitLabPublishConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	APIURL      string   `yaml:"api_url"`      // REST API base URL, defaults to $CI_API_V4_URL or https://gitlab.com/api/v4
+	ProjectID   string   `yaml:"project_id"`   // Numeric ID or "group/project", defaults to $CI_PROJECT_ID or repository.url
+	TokenEnv    string   `yaml:"token_env"`    // Environment variable holding a private token, $CI_JOB_TOKEN is the fallback
+	Assets      []string `yaml:"assets"`       // Glob patterns of files uploaded to the generic package registry
+	PackageName string   `yaml:"package_name"` // Generic package name, defaults to the project name
+	Milestones  []string `yaml:"milestones"`   // Milestone titles linked to the release, {version} is replaced
+}
+
+// GiteaPublishConfig holds the settings for creating Gitea and Forgejo releases
+type GiteaPublishConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	URL      string   `yaml:"url"`       // Base URL of the instance, detected from repository.url when empty
+	Owner    string   `yaml:"owner"`     // Detected from repository.url when empty
+	Repo     string   `yaml:"repo"`      // Detected from repository.url when empty
+	TokenEnv string   `yaml:"token_env"` // Environment variable holding the token
+	Assets   []string `yaml:"assets"`    // Glob patterns of files to upload
+	Draft    bool     `yaml:"draft"`
+}
+
+// PluginEvents are the release lifecycle points plugins can be invoked at
+var PluginEvents = []string{"version", "notes", "released"}
+
+// PluginConfig is an external executable invoked during a release
+type PluginConfig struct {
+	Name    string   `yaml:"name"`
+	Command string   `yaml:"command"` // Executable, looked up in PATH or relative to the working directory
+	Args    []string `yaml:"args"`
+	Events  []string `yaml:"events"`  // Lifecycle points the plugin is invoked at, all when empty
+	Timeout int      `yaml:"timeout"` // Seconds per invocation, 30 when zero
+}
+
+// HooksConfig holds the shell commands run around the steps of a release.
+// Each list runs in order and a failing command aborts the release.
+type HooksConfig struct {
+	PreVersion    []string `yaml:"pre_version"`    // Before the next version is calculated
+	PostVersion   []string `yaml:"post_version"`   // After the next version is calculated
+	PreChangelog  []string `yaml:"pre_changelog"`  // Before the changelog file is updated
+	PostChangelog []string `yaml:"post_changelog"` // After the changelog file is updated
+	PreTag        []string `yaml:"pre_tag"`        // Before the tag is created
+	PostTag       []string `yaml:"post_tag"`       // After the tag is created
+	PostRelease   []string `yaml:"post_release"`   // After the release is published, failures no longer roll back
+}
 
+// LockConfig holds the settings of the lock that keeps releases of a repository from running concurrently
+type LockConfig struct {
+	Enabled      bool   `yaml:"enabled"`       // Take a lockfile in the git directory
+	Remote       bool   `yaml:"remote"`        // Also take a lock ref on the remote, shared by all clones
+	RemoteName   string `yaml:"remote_name"`   // Remote holding the lock ref
+	Ref          string `yaml:"ref"`           // Lock ref on the remote
+	OnContention string `yaml:"on_contention"` // "wait", "fail" or "retry"
+	Timeout      int    `yaml:"timeout"`       // Seconds to wait for the lock
+	StaleAfter   int    `yaml:"stale_after"`   // Seconds after which a held lock is taken over, never when zero
+}
+
+// HookStages are the release lifecycle points hooks run at, in release order
+var HookStages = []string{"pre_version", "post_version", "pre_changelog", "post_changelog", "pre_tag", "post_tag", "post_release"}
+
+// Commands returns the commands configured for a stage
+func (h HooksConfig) Commands(stage string) []string {
+	switch stage {
+	case "pre_version":
+		return h.PreVersion
+	case "post_version":
+		return h.PostVersion
+	case "pre_changelog":
+		return h.PreChangelog
+	case "post_changelog":
+		return h.PostChangelog
+	case "pre_tag":
+		return h.PreTag
+	case "post_tag":
+		return h.PostTag
+	case "post_release":
+		return h.PostRelease
+	}
+	return nil
+}
 
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
@@ -63,47 +399,115 @@ func DefaultConfig() *Config {
 				"feat": {
 					Title:  "Features",
 					Semver: "minor",
+					Order:  1,
+					Hidden: boolPtr(false),
 				},
 				"fix": {
 					Title:  "Bug Fixes",
 					Semver: "patch",
+					Order:  2,
+					Hidden: boolPtr(false),
 				},
 				"docs": {
 					Title:  "Documentation",
 					Semver: "none",
+					Order:  3,
+					Hidden: boolPtr(true),
 				},
 				"style": {
 					Title:  "Styles",
 					Semver: "none",
+					Order:  4,
+					Hidden: boolPtr(true),
 				},
 				"refactor": {
 					Title:  "Code Refactoring",
 					Semver: "none",
+					Order:  5,
+					Hidden: boolPtr(true),
 				},
 				"test": {
 					Title:  "Tests",
 					Semver: "none",
+					Order:  6,
+					Hidden: boolPtr(true),
 				},
 				"chore": {
 					Title:  "Chores",
 					Semver: "none",
+					Order:  7,
+					Hidden: boolPtr(true),
+				},
+				"revert": {
+					Title:  "Reverts",
+					Semver: "patch",
+					Order:  8,
+					Hidden: boolPtr(false),
 				},
 			},
 			BreakingChangeKeywords: []string{"BREAKING CHANGE", "BREAKING-CHANGE"},
+			BreakingDetection:      "strict",
+			HeaderPattern:          "conventional",
+			NonConventional: NonConventionalConfig{
+				Policy:  "include",
+				Section: "Other",
+			},
+			MergeStrategy: "include",
+			Reverts: RevertsConfig{
+				Cancel: true,
+				List:   false,
+			},
 		},
 		Changelog: ChangelogConfig{
 			File:       "CHANGELOG.md",
 			Template:   "default",
 			IncludeAll: false,
+			GroupBy:    "type",
+			Contributors: ContributorsConfig{
+				Enabled:       false,
+				ExcludeBots:   true,
+				Bots:          []string{"dependabot", "renovate", "github-actions"},
+				MarkFirstTime: true,
+			},
 		},
 		Git: GitConfig{
 			TagMessage:      "Release {version}",
 			CommitChangelog: true,
 			CommitMessage:   "chore: update changelog for {version}",
 		},
+		Notify: NotifyConfig{
+			Retries: 3,
+			Timeout: 10,
+		},
+		Publish: PublishConfig{
+			Remote: "origin",
+			GitHub: GitHubPublishConfig{
+				APIURL:   "https://api.github.com",
+				TokenEnv: "GITHUB_TOKEN",
+			},
+			GitLab: GitLabPublishConfig{
+				TokenEnv: "GITLAB_ACCESS_TOKEN",
+			},
+			Gitea: GiteaPublishConfig{
+				TokenEnv: "GITEA_TOKEN",
+			},
+		},
+		Lock: LockConfig{
+			Enabled:      false,
+			RemoteName:   "origin",
+			Ref:          "refs/herald/lock",
+			OnContention: "wait",
+			Timeout:      600,
+			StaleAfter:   3600,
+		},
 	}
 }
 
+// boolPtr returns a pointer to a bool, for optional settings
+func boolPtr(value bool) *bool {
+	return &value
+}
+
 // LoadConfig loads configuration from a file or returns default config
 func LoadConfig(configFile string) (*Config, error) {
 	// Use provided config file or look for .heraldrc
@@ -128,9 +532,47 @@ func LoadConfig(configFile string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if err := config.loadContributorMapping(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", configFile, err)
+	}
+
 	return config, nil
 }
 
+// loadContributorMapping merges the contributors mapping file into the configured handles
+func (c *Config) loadContributorMapping() error {
+	mappingFile := c.Changelog.Contributors.MappingFile
+	if mappingFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(mappingFile)
+	if err != nil {
+		return fmt.Errorf("failed to read contributors mapping file: %w", err)
+	}
+
+	var handles map[string]string
+	if err := yaml.Unmarshal(data, &handles); err != nil {
+		return fmt.Errorf("failed to parse contributors mapping file: %w", err)
+	}
+
+	if c.Changelog.Contributors.Handles == nil {
+		c.Changelog.Contributors.Handles = make(map[string]string)
+	}
+	for email, handle := range handles {
+		// Entries in the config file take precedence
+		if _, exists := c.Changelog.Contributors.Handles[email]; !exists {
+			c.Changelog.Contributors.Handles[email] = handle
+		}
+	}
+
+	return nil
+}
+
 // InitializeConfig creates a default .heraldrc file
 func InitializeConfig() error {
 	configFile := ".heraldrc"
@@ -174,47 +616,159 @@ commits:
   # Each commit type can specify:
   #   title: Display name in changelog
   #   semver: Version bump level ("major", "minor", "patch", "none")
+  #   order: Position of the section in the changelog (lower comes first)
+  #   hidden: Leave out of the changelog unless breaking or include_all is true
   types:
     # New features - typically bump minor version
     feat:
       title: "Features"
       semver: "minor"
+      order: 1
+      hidden: false
     
     # Bug fixes - typically bump patch version
     fix:
       title: "Bug Fixes"
       semver: "patch"
+      order: 2
+      hidden: false
     
     # Documentation changes - no version bump by default
     docs:
       title: "Documentation"
       semver: "none"
+      order: 3
+      hidden: true
     
     # Code style changes (formatting, etc.) - no version bump
     style:
       title: "Styles"
       semver: "none"
+      order: 4
+      hidden: true
     
     # Code refactoring without functional changes - no version bump
     refactor:
       title: "Code Refactoring"
       semver: "none"
+      order: 5
+      hidden: true
     
     # Test additions or modifications - no version bump
     test:
       title: "Tests"
       semver: "none"
+      order: 6
+      hidden: true
     
     # Build process, tooling, dependencies - no version bump by default
     chore:
       title: "Chores"
       semver: "none"
+      order: 7
+      hidden: true
+    
+    # Reverts of changes from earlier releases - bump patch version
+    revert:
+      title: "Reverts"
+      semver: "patch"
+      order: 8
+      hidden: false
   
   # Keywords that indicate breaking changes (triggers major version bump)
-  # These can appear in commit body or footer
   breaking_change_keywords:
     - "BREAKING CHANGE"
     - "BREAKING-CHANGE"
+  
+  # Grammar of the commit header line
+  # Either a preset name:
+  #   conventional: type(scope)!: description (hyphenated types and "a,b" scopes allowed)
+  #   angular: Like conventional, limited to build, ci, docs, feat, fix, perf,
+  #            refactor, style, test, revert and chore
+  #   ticket: Conventional with an optional ticket prefix, "[PROJ-123] feat: description"
+  #   gitmoji: "✨ add login", ":bug: (auth): fix crash" or "✨ feat: add login",
+  #            see the gitmoji section below
+  # Or a regular expression with the named groups type and description, and
+  # optionally scope, breaking (non-empty when breaking) and ticket:
+  # header_pattern: '^(?P<ticket>[A-Z]+-\d+) (?P<type>\w+): (?P<description>.+)$'
+  header_pattern: "conventional"
+  
+  # Gitmoji preset settings
+  # Gitmojis map to commit types (✨ feat, 🐛 fix, 📝 docs, ♻️ refactor, ...) and
+  # the semver level follows the type unless the mapping sets one; 💥 marks a breaking change
+  gitmoji:
+    # Prefix changelog section titles with the emoji of the type ("✨ Features")
+    emoji_headings: false
+    
+    # Additional or overriding mappings, by emoji or shortcode
+    # map:
+    #   "⚡️": { type: "perf", semver: "minor" }
+    #   ":boom:": { type: "feat", breaking: true }
+  
+  # How breaking changes are detected
+  #   strict: As in the Conventional Commits spec, "!" right before the colon
+  #           of the header (feat!: or feat(api)!:), or a keyword as a footer
+  #           token ("BREAKING CHANGE: description")
+  #   lenient: "!:" anywhere in the subject, or a keyword anywhere in the message
+  breaking_detection: "strict"
+  
+  # Commits that do not follow the conventional commits format
+  # (merge commits, "WIP", "Update README", ...)
+  non_conventional:
+    # What to do with them:
+    #   include: List them in the changelog under the section below
+    #   ignore: Leave them out of the changelog silently
+    #   warn: Leave them out and print a warning listing them
+    #   fail: Abort the release, listing them
+    # Run "herald lint" to list them before releasing
+    policy: "include"
+    
+    # Changelog section title used by the "include" policy
+    section: "Other"
+  
+  # How merge commits ("Merge pull request #42 from org/feature-x") are handled
+  #   include: Treat merge commits like any other commit
+  #   skip: Leave merge commits out, keep the commits of merged branches
+  #   first-parent: Only follow the main line history
+  #   parse: Follow the main line and take the conventional message from the
+  #          merge body (PR title), or from the merged branch commits
+  merge_strategy: "include"
+  
+  # Display names and aliases for commit scopes
+  # Commits using an alias are grouped under the scope they belong to
+  # scopes:
+  #   web:
+  #     title: "Web App"
+  #     aliases: ["ui", "frontend"]
+  
+  # Only allow the scopes (and aliases) listed above; enforced by "herald lint"
+  restrict_scopes: false
+  
+  # Rules overriding the version bump of matching commits
+  # Rules are checked in order and the first match wins; commits without a
+  # matching rule bump according to their type (or major when breaking)
+  # Matchers (all optional, every given matcher must match):
+  #   type, scope: Commit type and scope (aliases are resolved first)
+  #   path: Glob that every file changed by the commit must match ("**" spans directories)
+  #   breaking: true or false
+  # Effect: semver (major, minor, patch, none) or exclude: true
+  # rules:
+  #   - scope: "examples"
+  #     exclude: true
+  #   - type: "fix"
+  #     scope: "api"
+  #     semver: "minor"
+  #   - path: "docs/**"
+  #     semver: "none"
+  
+  # Reverts ("Revert \"feat: add X\"" from git revert, or "revert:" commits)
+  reverts:
+    # Drop a commit and its revert from the version bump and the changelog
+    # when both are part of the same release
+    cancel: true
+    
+    # List the cancelled pairs in the changelog under "Reverted"
+    list: false
 
 # Changelog Configuration
 changelog:
@@ -227,9 +781,41 @@ changelog:
   
   # Whether to include all commit types in changelog
   # true: Include all configured commit types
-  # false: Only include "feat" and "fix" (plus breaking changes)
+  # false: Only include types that are not hidden (plus breaking changes)
   include_all: false
 
+  # How entries are grouped into sections
+  #   type: One section per commit type (Features, Bug Fixes, ...)
+  #   scope: One section per scope, using the scope display names
+  #   type-then-scope: Sections per type with nested sections per scope
+  group_by: "type"
+
+  # Keep a "## [Unreleased]" section at the top of the changelog
+  # Notes written there by hand are moved into the next release section
+  unreleased: false
+
+  # Contributors section listing the authors of each release
+  contributors:
+    # Add a "Contributors" section to each release
+    enabled: false
+    
+    # Map commit emails to handles, shown as "@handle"
+    # handles:
+    #   "jane@example.com": "jane"
+    
+    # Optional YAML file with more email to handle entries
+    mapping_file: ""
+    
+    # Leave bots out of the contributors list
+    exclude_bots: true
+    bots:
+      - "dependabot"
+      - "renovate"
+      - "github-actions"
+    
+    # Mark authors that never appeared in earlier history
+    mark_first_time: true
+
 # Git Configuration
 git:
   # Message template for git tags
@@ -242,6 +828,210 @@ git:
   # Commit message template when committing changelog
   # {version} will be replaced with the actual version
   commit_message: "chore: update changelog for {version}"
+
+# Repository Configuration
+# Used to render commit, compare and issue links in the changelog
+repository:
+  # Web URL of the repository (e.g., "https://github.com/owner/repo")
+  # Leave empty to detect it from the "origin" remote
+  url: ""
+  
+  # Hosting provider: "github", "gitlab", "gitea" or "bitbucket"
+  # Leave empty to detect it from the URL
+  provider: ""
+  
+  # Optional URL templates overriding the provider defaults
+  # commit_url: "{url}/commit/{hash}"
+  # compare_url: "{url}/compare/{previous}...{current}"
+  # issue_url: "{url}/issues/{id}"
+  
+  # Issue trackers referenced from commit headers and from "Refs:", "Closes:",
+  # "Fixes:" and "Resolves:" footers. Each tracker has a name, a pattern whose
+  # "id" group (or whole match) is the issue key, and a URL template with {id}.
+  # "#123" references use the built-in "repository" tracker and issue_url
+  # trackers:
+  #   - name: "jira"
+  #     pattern: '\b(?P<id>PROJ-\d+)\b'
+  #     url: "https://example.atlassian.net/browse/{id}"
+  #   - name: "linear"
+  #     pattern: '\b(?P<id>ENG-\d+)\b'
+  #     url: "https://linear.app/example/issue/{id}"
+
+# Release Notifications
+# Posted after a successful release; "herald release --dry-run" prints the payloads
+notify:
+  # Targets, each with a name, a type and a webhook URL
+  # Types: "slack", "discord", "teams" (incoming webhooks) or "webhook"
+  # (generic JSON POST with the version and the notes)
+  # ${VAR} in url and headers is read from the environment
+  # targets:
+  #   - name: "team-chat"
+  #     type: "slack"
+  #     url: "${SLACK_WEBHOOK_URL}"
+  #   - name: "deploy-bot"
+  #     type: "webhook"
+  #     url: "https://deploy.example.com/hooks/release"
+  #     headers:
+  #       Authorization: "Bearer ${DEPLOY_TOKEN}"
+  
+  # Additional attempts after a failed post (network errors, 429 and 5xx responses)
+  retries: 3
+  
+  # Request timeout in seconds
+  timeout: 10
+
+# Release Publishing
+# Creates a release on the hosting provider after tagging; "herald publish"
+# publishes an existing tag, e.g. after pushing it from CI, and
+# "herald publish --retry" resumes the publishers that failed
+publish:
+  # Order of the publishers: "github", "gitlab", "gitea" and "notify"
+  # (the notifications configured above). Publishers not listed run
+  # afterwards in this default order
+  # order: ["gitlab", "notify"]
+  
+  # Remote the release tag is pushed to. "herald release" only publishes
+  # once the tag is on this remote, otherwise run "herald publish" after pushing
+  remote: "origin"
+  
+  # GitHub Releases
+  github:
+    # Create or update the GitHub Release of the new tag
+    enabled: false
+    
+    # REST API base URL; use "https://HOST/api/v3" for GitHub Enterprise Server
+    api_url: "https://api.github.com"
+    
+    # Repository owner and name; leave empty to detect them from repository.url
+    owner: ""
+    repo: ""
+    
+    # Environment variable holding a token with "contents: write" permission
+    token_env: "GITHUB_TOKEN"
+    
+    # Glob patterns of files uploaded as release assets
+    # Assets with the same name are replaced
+    # assets:
+    #   - "dist/*.tar.gz"
+    #   - "dist/checksums.txt"
+    
+    # Create the release as a draft
+    draft: false
+  
+  # GitLab releases
+  gitlab:
+    # Create or update the GitLab release of the new tag
+    enabled: false
+    
+    # REST API base URL for self-hosted instances (e.g., "https://gitlab.example.com/api/v4")
+    # Leave empty to use $CI_API_V4_URL in GitLab CI, or https://gitlab.com/api/v4
+    api_url: ""
+    
+    # Numeric project ID or "group/project" path
+    # Leave empty to use $CI_PROJECT_ID in GitLab CI, or the path of repository.url
+    project_id: ""
+    
+    # Environment variable holding a token with the "api" scope
+    # $CI_JOB_TOKEN is used when it is not set
+    token_env: "GITLAB_ACCESS_TOKEN"
+    
+    # Glob patterns of files uploaded to the generic package registry
+    # and linked from the release
+    # assets:
+    #   - "dist/*.tar.gz"
+    
+    # Generic package name; leave empty to use the project name
+    package_name: ""
+    
+    # Titles of existing milestones linked to the release
+    # {version} will be replaced with the actual version
+    # milestones:
+    #   - "{version}"
+  
+  # Gitea and Forgejo releases
+  gitea:
+    # Create or update the release of the new tag
+    enabled: false
+    
+    # Base URL of the instance (e.g., "https://git.example.com")
+    # Leave empty to use the host of repository.url
+    url: ""
+    
+    # Repository owner and name; leave empty to detect them from repository.url
+    owner: ""
+    repo: ""
+    
+    # Environment variable holding an access token with repository write access
+    token_env: "GITEA_TOKEN"
+    
+    # Glob patterns of files uploaded as release attachments
+    # Attachments with the same name are replaced
+    # assets:
+    #   - "dist/*.tar.gz"
+    
+    # Create the release as a draft
+    draft: false
+
+# Plugins
+# Executables written in any language, invoked during "herald release".
+# Herald writes a JSON document with the configuration, versions, commits and
+# release notes to the plugin's stdin and reads changes back from its stdout.
+# Events:
+# - "version": after the next version is calculated; may set "version" or "veto"
+# - "notes": after the release notes are generated; may add "notes" or "veto"
+# - "released": after the release is published; informational only
+# plugins:
+#   - name: "jira-notes"
+#     command: "./scripts/jira-notes.py"
+#     args: ["--project", "PROJ"]
+#     events: ["notes"]
+#     timeout: 30
+
+# Hooks
+# Shell commands run around the steps of "herald release", in order.
+# They get HERALD_VERSION, HERALD_PREVIOUS_VERSION and HERALD_BUMP in their
+# environment (HERALD_VERSION and HERALD_BUMP are empty in pre_version).
+# A failing hook aborts the release, deletes the tag and discards the changelog
+# update and the files changed by hooks. Herald does not commit: the updated
+# changelog and the files changed by hooks are left in the working directory.
+# hooks:
+#   pre_version: []
+#   post_version:
+#     - "npm version --no-git-tag-version $HERALD_VERSION"
+#   pre_changelog: []
+#   post_changelog:
+#     - "npm install --package-lock-only"
+#   pre_tag:
+#     - "npm run build"
+#   post_tag: []
+#   post_release:
+#     - "./scripts/announce.sh"
+
+# Release Lock
+# Keeps "herald release" runs of the same repository from racing each other.
+lock:
+  # Take a lockfile in the git directory (.git/herald/release.lock)
+  enabled: false
+  
+  # Also take a lock ref on the remote, pushed atomically, so that
+  # pipelines working in separate clones exclude each other.
+  # Needs push access to the remote.
+  remote: false
+  remote_name: "origin"
+  ref: "refs/herald/lock"
+  
+  # What to do when another release holds the lock:
+  # - "wait": wait for it, then release from the local state
+  # - "fail": stop with an error
+  # - "retry": wait for it, then fetch tags from the remote and recompute the release
+  on_contention: "wait"
+  
+  # Seconds to wait for the lock before giving up
+  timeout: 600
+  
+  # Seconds after which a lock left behind by a crashed run is taken over (0 = never).
+  # A lock taken on this host by a process that is no longer running is taken over at once.
+  stale_after: 3600
 `
 }
 
@@ -265,7 +1055,7 @@ func (c *Config) Validate() error {
 		if typeConfig.Title == "" {
 			return fmt.Errorf("commit type '%s' must have a title", commitType)
 		}
-		
+
 		validSemver := false
 		for _, validLevel := range validSemverLevels {
 			if strings.ToLower(typeConfig.Semver) == validLevel {
@@ -276,6 +1066,124 @@ func (c *Config) Validate() error {
 		if !validSemver {
 			return fmt.Errorf("commit type '%s' has invalid semver level '%s' (must be: major, minor, patch, or none)", commitType, typeConfig.Semver)
 		}
+
+		if typeConfig.Order < 0 {
+			return fmt.Errorf("commit type '%s' has negative order %d", commitType, typeConfig.Order)
+		}
+	}
+
+	for i, rule := range c.Commits.Rules {
+		if rule.Type == "" && rule.Scope == "" && rule.Path == "" && rule.Breaking == nil {
+			return fmt.Errorf("commits.rules[%d] must set at least one of type, scope, path, or breaking", i)
+		}
+		if rule.Exclude {
+			if rule.Semver != "" {
+				return fmt.Errorf("commits.rules[%d] cannot set both semver and exclude", i)
+			}
+			continue
+		}
+		switch strings.ToLower(rule.Semver) {
+		case "major", "minor", "patch", "none":
+		default:
+			return fmt.Errorf("commits.rules[%d] has invalid semver level '%s' (must be: major, minor, patch, or none, or set exclude)", i, rule.Semver)
+		}
+	}
+
+	switch strings.ToLower(c.Changelog.GroupBy) {
+	case "", "type", "scope", "type-then-scope":
+	default:
+		return fmt.Errorf("changelog.group_by has invalid value '%s' (must be: type, scope, or type-then-scope)", c.Changelog.GroupBy)
+	}
+
+	if _, err := c.Commits.HeaderRegexp(); err != nil {
+		return err
+	}
+
+	for i, target := range c.Notify.Targets {
+		switch strings.ToLower(target.Type) {
+		case "slack", "discord", "teams", "webhook":
+		default:
+			return fmt.Errorf("notify.targets[%d] has invalid type '%s' (must be: slack, discord, teams, or webhook)", i, target.Type)
+		}
+		if target.URL == "" {
+			return fmt.Errorf("notify.targets[%d] must have a url", i)
+		}
+	}
+	if c.Notify.Retries < 0 || c.Notify.Timeout < 0 {
+		return fmt.Errorf("notify.retries and notify.timeout cannot be negative")
+	}
+
+	for i, plugin := range c.Plugins {
+		if plugin.Command == "" {
+			return fmt.Errorf("plugins[%d] must have a command", i)
+		}
+		for _, event := range plugin.Events {
+			if !slices.Contains(PluginEvents, event) {
+				return fmt.Errorf("plugins[%d] has invalid event '%s' (must be: %s)", i, event, strings.Join(PluginEvents, ", "))
+			}
+		}
+		if plugin.Timeout < 0 {
+			return fmt.Errorf("plugins[%d].timeout cannot be negative", i)
+		}
+	}
+
+	switch c.Lock.OnContention {
+	case "wait", "fail", "retry":
+	default:
+		return fmt.Errorf("lock.on_contention has invalid value '%s' (must be: wait, fail, or retry)", c.Lock.OnContention)
+	}
+	if c.Lock.Remote && (c.Lock.RemoteName == "" || !strings.HasPrefix(c.Lock.Ref, "refs/")) {
+		return fmt.Errorf("lock.remote needs a lock.remote_name and a lock.ref starting with refs/")
+	}
+	if c.Lock.Timeout < 0 || c.Lock.StaleAfter < 0 {
+		return fmt.Errorf("lock.timeout and lock.stale_after cannot be negative")
+	}
+
+	for _, stage := range HookStages {
+		for i, command := range c.Hooks.Commands(stage) {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("hooks.%s[%d] cannot be empty", stage, i)
+			}
+		}
+	}
+
+	for i, tracker := range c.Repository.Trackers {
+		if tracker.Name == "" {
+			return fmt.Errorf("repository.trackers[%d] must have a name", i)
+		}
+		if tracker.Pattern == "" && tracker.Name != RepositoryTracker {
+			return fmt.Errorf("repository.trackers '%s' must have a pattern", tracker.Name)
+		}
+		if _, err := tracker.Regexp(); err != nil {
+			return err
+		}
+	}
+
+	for key, mapping := range c.Commits.Gitmoji.Map {
+		if mapping.Type == "" {
+			return fmt.Errorf("commits.gitmoji.map entry '%s' must set a type", key)
+		}
+		if mapping.Semver != "" && !slices.Contains(validSemverLevels, mapping.Semver) {
+			return fmt.Errorf("commits.gitmoji.map entry '%s' has invalid semver level '%s' (must be: major, minor, patch, or none)", key, mapping.Semver)
+		}
+	}
+
+	switch strings.ToLower(c.Commits.BreakingDetection) {
+	case "", "strict", "lenient":
+	default:
+		return fmt.Errorf("commits.breaking_detection has invalid value '%s' (must be: strict or lenient)", c.Commits.BreakingDetection)
+	}
+
+	switch strings.ToLower(c.Commits.MergeStrategy) {
+	case "", "include", "skip", "first-parent", "parse":
+	default:
+		return fmt.Errorf("commits.merge_strategy has invalid value '%s' (must be: include, skip, first-parent, or parse)", c.Commits.MergeStrategy)
+	}
+
+	switch strings.ToLower(c.Commits.NonConventional.Policy) {
+	case "", "ignore", "include", "warn", "fail":
+	default:
+		return fmt.Errorf("commits.non_conventional.policy has invalid value '%s' (must be: ignore, include, warn, or fail)", c.Commits.NonConventional.Policy)
 	}
 
 	return nil
@@ -301,4 +1209,4 @@ func GetConfigPath(configFile string) string {
 	}
 
 	return ".heraldrc" // Default fallback
-} 
\ No newline at end of file
+}
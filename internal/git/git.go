@@ -12,7 +12,15 @@ import (
 
 // Repository wraps git repository operations using git commands
 type Repository struct {
-	path string
+	path       string
+	logOptions LogOptions
+}
+
+// LogOptions selects which commits the history queries return
+type LogOptions struct {
+	FirstParent bool // Follow only the first parent of merge commits
+	NoMerges    bool // Leave out merge commits
+	Files       bool // Load the files changed by each commit
 }
 
 // Commit represents a git commit with parsed information
@@ -24,6 +32,13 @@ type Commit struct {
 	Date      time.Time
 	Subject   string
 	Body      string
+	Parents   []string
+	Files     []string // Changed files, only loaded when LogOptions.Files is set
+}
+
+// IsMerge returns true if the commit has more than one parent
+func (c *Commit) IsMerge() bool {
+	return len(c.Parents) > 1
 }
 
 // Tag represents a git tag
@@ -107,13 +122,61 @@ func (r *Repository) GetLatestTag() (*Tag, error) {
 
 // GetCommitsSinceTag returns all commits since the specified tag
 func (r *Repository) GetCommitsSinceTag(tagName string) ([]*Commit, error) {
-	var args []string
-	if tagName != "" {
-		args = []string{"log", "--oneline", "--format=%H|%an|%ae|%at|%s|%b", tagName + "..HEAD"}
-	} else {
-		args = []string{"log", "--oneline", "--format=%H|%an|%ae|%at|%s|%b"}
+	return r.GetCommitsBetween(tagName, "HEAD")
+}
+
+// GetCommitsBetween returns the commits reachable from toRef but not from fromRef.
+// An empty fromRef returns the full history up to toRef.
+func (r *Repository) GetCommitsBetween(fromRef, toRef string) ([]*Commit, error) {
+	if toRef == "" {
+		toRef = "HEAD"
 	}
 
+	revRange := toRef
+	if fromRef != "" {
+		revRange = fromRef + ".." + toRef
+	}
+
+	var flags []string
+	if r.logOptions.FirstParent {
+		flags = append(flags, "--first-parent")
+	}
+	if r.logOptions.NoMerges {
+		flags = append(flags, "--no-merges")
+	}
+	if r.logOptions.Files {
+		flags = append(flags, "--name-only")
+	}
+
+	return r.log(revRange, flags...)
+}
+
+// GetMergedCommits returns the commits a merge commit brought in from its merged branches
+func (r *Repository) GetMergedCommits(merge *Commit) ([]*Commit, error) {
+	if !merge.IsMerge() {
+		return []*Commit{}, nil
+	}
+	flags := []string{"--no-merges"}
+	if r.logOptions.Files {
+		flags = append(flags, "--name-only")
+	}
+	return r.log(merge.Parents[0]+".."+merge.Hash, flags...)
+}
+
+// SetLogOptions changes which commits the history queries return
+func (r *Repository) SetLogOptions(options LogOptions) {
+	r.logOptions = options
+}
+
+// log runs git log for a revision range and parses the commits
+func (r *Repository) log(revRange string, flags ...string) ([]*Commit, error) {
+	// Records are separated by \x1e and fields by \x1f so that multi-line
+	// bodies survive intact. The trailing separator keeps the --name-only
+	// file list apart from the body.
+	args := []string{"log", "--format=%x1e%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%s%x1f%b%x1f"}
+	args = append(args, flags...)
+	args = append(args, revRange)
+
 	output, err := r.runGitCommand(args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commits: %w", err)
@@ -124,37 +187,45 @@ func (r *Repository) GetCommitsSinceTag(tagName string) ([]*Commit, error) {
 	}
 
 	var commits []*Commit
-	lines := strings.Split(output, "\n")
+	records := strings.Split(output, "\x1e")
 
-	for _, line := range lines {
-		if line == "" {
+	for _, record := range records {
+		if strings.TrimSpace(record) == "" {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
-		if len(parts) < 5 {
+		parts := strings.SplitN(record, "\x1f", 8)
+		if len(parts) < 6 {
 			continue
 		}
 
 		// Parse timestamp
-		timestamp, err := strconv.ParseInt(parts[3], 10, 64)
+		timestamp, err := strconv.ParseInt(parts[4], 10, 64)
 		if err != nil {
 			timestamp = time.Now().Unix()
 		}
 
 		var body string
-		if len(parts) > 5 {
-			body = strings.Join(parts[5:], "|")
+		if len(parts) > 6 {
+			body = strings.TrimSpace(parts[6])
 		}
 
 		commit := &Commit{
 			Hash:    parts[0],
-			Author:  parts[1],
-			Email:   parts[2],
+			Parents: strings.Fields(parts[1]),
+			Author:  parts[2],
+			Email:   parts[3],
 			Date:    time.Unix(timestamp, 0),
-			Subject: parts[4],
+			Subject: parts[5],
 			Body:    body,
-			Message: parts[4] + "\n\n" + body,
+			Message: parts[5] + "\n\n" + body,
+		}
+		if len(parts) > 7 {
+			for _, file := range strings.Split(parts[7], "\n") {
+				if file = strings.TrimSpace(file); file != "" {
+					commit.Files = append(commit.Files, file)
+				}
+			}
 		}
 
 		commits = append(commits, commit)
@@ -193,6 +264,29 @@ func (r *Repository) CreateTag(name, message string) error {
 	return nil
 }
 
+// DeleteTag deletes a local tag
+func (r *Repository) DeleteTag(name string) error {
+	if _, err := r.runGitCommand("tag", "-d", name); err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Restore resets the current branch and working directory to a commit and removes
+// untracked files, leaving ignored files alone
+func (r *Repository) Restore(ref string) error {
+	if _, err := r.runGitCommand("reset", "--hard", ref); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
+
+	if _, err := r.runGitCommand("clean", "-fd"); err != nil {
+		return fmt.Errorf("failed to remove untracked files: %w", err)
+	}
+
+	return nil
+}
+
 // GetTags returns all tags in the repository
 func (r *Repository) GetTags() ([]*Tag, error) {
 	output, err := r.runGitCommand("tag", "-l", "--sort=-creatordate", "--format=%(refname:short)|%(creatordate:iso)|%(objectname)|%(contents:subject)")
@@ -261,4 +355,139 @@ func (r *Repository) GetCurrentBranch() (string, error) {
 	}
 
 	return branch, nil
-} 
\ No newline at end of file
+} 
+
+// GetRemoteURL returns the URL of the named remote
+func (r *Repository) GetRemoteURL(name string) (string, error) {
+	if name == "" {
+		name = "origin"
+	}
+
+	url, err := r.runGitCommand("remote", "get-url", name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote %s: %w", name, err)
+	}
+
+	return url, nil
+}
+
+// GitDir returns the absolute path of the repository's git directory, where herald keeps its state
+func (r *Repository) GitDir() (string, error) {
+	gitDir, err := r.runGitCommand("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+
+	return gitDir, nil
+}
+
+// ResolveCommit returns the hash of the commit a ref points to, peeling annotated tags
+func (r *Repository) ResolveCommit(ref string) (string, error) {
+	hash, err := r.runGitCommand("rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	return hash, nil
+}
+
+// CreateDetachedCommit creates a commit with an empty tree that no branch points to
+func (r *Repository) CreateDetachedCommit(message string) (string, error) {
+	tree, err := r.runGitCommand("mktree")
+	if err != nil {
+		return "", fmt.Errorf("failed to create empty tree: %w", err)
+	}
+
+	// An identity is set so that it works in CI clones without one
+	hash, err := r.runGitCommand("-c", "user.name=herald", "-c", "user.email=herald@localhost", "commit-tree", tree, "-m", message)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	return hash, nil
+}
+
+// GetCommitMessage returns the full message of a commit
+func (r *Repository) GetCommitMessage(ref string) (string, error) {
+	message, err := r.runGitCommand("log", "-1", "--format=%B", ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", ref, err)
+	}
+
+	return message, nil
+}
+
+// GetRemoteRef returns the hash a ref points to on a remote, or an empty string when it does not exist
+func (r *Repository) GetRemoteRef(remote, ref string) (string, error) {
+	output, err := r.runGitCommand("ls-remote", remote, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s on remote %s: %w", ref, remote, err)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0], nil
+		}
+	}
+
+	return "", nil
+}
+
+// FetchRef fetches a ref from a remote so that the commit it points to is available locally
+func (r *Repository) FetchRef(remote, ref string) error {
+	if _, err := r.runGitCommand("fetch", "--quiet", "--no-tags", remote, ref); err != nil {
+		return fmt.Errorf("failed to fetch %s from remote %s: %w", ref, remote, err)
+	}
+
+	return nil
+}
+
+// FetchTags fetches all tags from a remote
+func (r *Repository) FetchTags(remote string) error {
+	if _, err := r.runGitCommand("fetch", "--quiet", "--tags", remote); err != nil {
+		return fmt.Errorf("failed to fetch tags from remote %s: %w", remote, err)
+	}
+
+	return nil
+}
+
+// PushRef atomically points a ref on a remote to a commit, only when the ref still points to
+// expected. An empty expected requires the ref not to exist, an empty commit deletes the ref.
+func (r *Repository) PushRef(remote, ref, commit, expected string) error {
+	_, err := r.runGitCommand("push", "--quiet", "--atomic", "--no-verify",
+		"--force-with-lease="+ref+":"+expected, remote, commit+":"+ref)
+	if err != nil {
+		return fmt.Errorf("failed to push %s to remote %s: %w", ref, remote, err)
+	}
+
+	return nil
+}
+
+// GetAuthorEmails returns the lowercased emails of all authors and co-authors reachable from ref
+func (r *Repository) GetAuthorEmails(ref string) (map[string]bool, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	output, err := r.runGitCommand("log", "--format=%ae%n%(trailers:key=Co-authored-by,valueonly)", ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+
+	emails := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		// Co-author trailers have the form "Name <email>"
+		if start := strings.Index(line, "<"); start >= 0 {
+			if end := strings.Index(line[start:], ">"); end > 0 {
+				line = line[start+1 : start+end]
+			}
+		}
+		if line != "" {
+			emails[strings.ToLower(line)] = true
+		}
+	}
+
+	return emails, nil
+}
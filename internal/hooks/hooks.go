@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"herald/internal/config"
+)
+
+// Lifecycle points hooks run at
+const (
+	PreVersion    = "pre_version"
+	PostVersion   = "post_version"
+	PreChangelog  = "pre_changelog"
+	PostChangelog = "post_changelog"
+	PreTag        = "pre_tag"
+	PostTag       = "post_tag"
+	PostRelease   = "post_release"
+)
+
+// Env is the release information passed to hooks as environment variables
+type Env struct {
+	Version         string // HERALD_VERSION, empty before the version is calculated
+	PreviousVersion string // HERALD_PREVIOUS_VERSION, empty for the first release
+	Bump            string // HERALD_BUMP, empty before the version is calculated
+}
+
+// Runner runs the configured hook commands through the shell
+type Runner struct {
+	config config.HooksConfig
+	dryRun bool
+}
+
+// NewRunner creates a hook runner. Commands run in the working directory.
+func NewRunner(cfg *config.Config, dryRun bool) *Runner {
+	return &Runner{
+		config: cfg.Hooks,
+		dryRun: dryRun,
+	}
+}
+
+// Run runs the commands of a stage in order and stops at the first failure.
+// In dry-run mode the commands are only listed.
+func (r *Runner) Run(stage string, env Env) error {
+	for _, command := range r.config.Commands(stage) {
+		if r.dryRun {
+			fmt.Printf("Would run %s hook: %s\n", stage, command)
+			continue
+		}
+
+		fmt.Printf("Running %s hook: %s\n", stage, command)
+		cmd := shellCommand(command)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(),
+			"HERALD_HOOK="+stage,
+			"HERALD_VERSION="+env.Version,
+			"HERALD_PREVIOUS_VERSION="+env.PreviousVersion,
+			"HERALD_BUMP="+env.Bump,
+		)
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook '%s' failed: %w", stage, command, err)
+		}
+	}
+	return nil
+}
+
+// shellCommand runs a command line through the platform shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
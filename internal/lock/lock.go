@@ -0,0 +1,305 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+// pollInterval is the time between attempts to take a held lock
+var pollInterval = 2 * time.Second
+
+// Info identifies the release holding a lock
+type Info struct {
+	Owner string    `json:"owner"` // CI job URL, or user@host outside CI
+	Host  string    `json:"host"`
+	PID   int       `json:"pid"`
+	Time  time.Time `json:"time"`
+}
+
+// String describes the holder for messages
+func (i *Info) String() string {
+	if i == nil || i.Owner == "" {
+		return "another release"
+	}
+	return fmt.Sprintf("%s (pid %d) since %s", i.Owner, i.PID, i.Time.Local().Format(time.RFC3339))
+}
+
+// ContentionError reports a lock held by another release
+type ContentionError struct {
+	Lock   string
+	Holder *Info
+}
+
+func (e *ContentionError) Error() string {
+	return fmt.Sprintf("%s is held by %s", e.Lock, e.Holder)
+}
+
+// Lock keeps releases of a repository from running concurrently. It consists of a
+// lockfile in the git directory and, optionally, a ref on the remote.
+type Lock struct {
+	repo   *git.Repository
+	config config.LockConfig
+	path   string
+	info   *Info
+
+	localHeld    bool
+	remoteCommit string // Commit of the remote lock ref while it is held
+}
+
+// New prepares the release lock of a repository
+func New(cfg *config.Config, repo *git.Repository) (*Lock, error) {
+	gitDir, err := repo.GitDir()
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	return &Lock{
+		repo:   repo,
+		config: cfg.Lock,
+		path:   filepath.Join(gitDir, "herald", "release.lock"),
+		info: &Info{
+			Owner: owner(host),
+			Host:  host,
+			PID:   os.Getpid(),
+		},
+	}, nil
+}
+
+// Acquire takes the lockfile and then the remote lock, as configured, handling contention as
+// set by lock.on_contention. It reports whether it had to wait for another release.
+func (l *Lock) Acquire() (bool, error) {
+	deadline := time.Now().Add(time.Duration(l.config.Timeout) * time.Second)
+	l.info.Time = time.Now().UTC()
+
+	waitedLocal, err := l.wait("release lockfile "+l.path, l.tryLocal, deadline, l.config.Enabled)
+	if err != nil {
+		return false, err
+	}
+
+	waitedRemote, err := l.wait(fmt.Sprintf("release lock %s on remote %s", l.config.Ref, l.config.RemoteName), l.tryRemote, deadline, l.config.Remote)
+	if err != nil {
+		if releaseErr := l.Release(); releaseErr != nil {
+			return false, fmt.Errorf("%w (%v)", err, releaseErr)
+		}
+		return false, err
+	}
+
+	return waitedLocal || waitedRemote, nil
+}
+
+// Release gives up the locks that are held. The remote ref is only deleted while it is still ours.
+func (l *Lock) Release() error {
+	var firstErr error
+
+	if l.remoteCommit != "" {
+		if err := l.repo.PushRef(l.config.RemoteName, l.config.Ref, "", l.remoteCommit); err != nil {
+			firstErr = fmt.Errorf("failed to release remote lock: %w", err)
+		}
+		l.remoteCommit = ""
+	}
+
+	if l.localHeld {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) && firstErr == nil {
+			firstErr = fmt.Errorf("failed to remove release lockfile: %w", err)
+		}
+		l.localHeld = false
+	}
+
+	return firstErr
+}
+
+// wait calls try until it takes a lock. try returns the holder when the lock is held by another release.
+func (l *Lock) wait(name string, try func() (*Info, error), deadline time.Time, enabled bool) (bool, error) {
+	if !enabled {
+		return false, nil
+	}
+
+	waited := false
+	for {
+		holder, err := try()
+		if err != nil {
+			return waited, err
+		}
+		if holder == nil {
+			return waited, nil
+		}
+
+		contention := &ContentionError{Lock: name, Holder: holder}
+		if l.config.OnContention == "fail" {
+			return waited, contention
+		}
+		if time.Now().After(deadline) {
+			return waited, fmt.Errorf("timed out after %ds waiting for the lock: %w", l.config.Timeout, contention)
+		}
+		if !waited {
+			fmt.Printf("Waiting for the release lock, %v\n", contention)
+			waited = true
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// tryLocal creates the lockfile, which fails when it already exists
+func (l *Lock) tryLocal() (*Info, error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	data, err := json.Marshal(l.info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lock: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		holder := readInfo(l.path)
+		if !l.stale(holder) {
+			return holder, nil
+		}
+		fmt.Printf("Removing stale release lockfile held by %s\n", holder)
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale release lockfile: %w", err)
+		}
+		return l.tryLocal()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release lockfile: %w", err)
+	}
+
+	l.localHeld = true
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write release lockfile: %w", err)
+	}
+	return nil, nil
+}
+
+// tryRemote points the lock ref on the remote to a new commit describing this release.
+// The push only succeeds while the ref is still missing, or still points to the stale lock it replaces.
+func (l *Lock) tryRemote() (*Info, error) {
+	current, err := l.repo.GetRemoteRef(l.config.RemoteName, l.config.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if current != "" {
+		holder := l.remoteHolder(current)
+		if !l.stale(holder) {
+			return holder, nil
+		}
+		fmt.Printf("Taking over stale release lock held by %s\n", holder)
+	}
+
+	data, err := json.Marshal(l.info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lock: %w", err)
+	}
+	commit, err := l.repo.CreateDetachedCommit(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.repo.PushRef(l.config.RemoteName, l.config.Ref, commit, current); err != nil {
+		// The push is rejected when another release moved the ref first
+		now, lsErr := l.repo.GetRemoteRef(l.config.RemoteName, l.config.Ref)
+		if lsErr != nil || now == current {
+			return nil, err
+		}
+		return l.remoteHolder(now), nil
+	}
+
+	l.remoteCommit = commit
+	return nil, nil
+}
+
+// remoteHolder reads the holder from the commit of the remote lock ref, nil when it cannot be read
+func (l *Lock) remoteHolder(commit string) *Info {
+	if err := l.repo.FetchRef(l.config.RemoteName, l.config.Ref); err != nil {
+		return nil
+	}
+	message, err := l.repo.GetCommitMessage(commit)
+	if err != nil {
+		return nil
+	}
+
+	var holder Info
+	if err := json.Unmarshal([]byte(message), &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
+
+// stale reports whether a lock was left behind: it was taken on this host by a process
+// that is no longer running, or longer than lock.stale_after ago. Locks of unknown
+// holders are never stale.
+func (l *Lock) stale(holder *Info) bool {
+	if holder == nil {
+		return false
+	}
+	if holder.Host != "" && holder.Host == l.info.Host && holder.PID > 0 && !processRunning(holder.PID) {
+		return true
+	}
+	if l.config.StaleAfter <= 0 || holder.Time.IsZero() {
+		return false
+	}
+	return time.Since(holder.Time) > time.Duration(l.config.StaleAfter)*time.Second
+}
+
+// processRunning reports whether a process of this host is still running.
+// On Windows, finding the process already fails once it has exited.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
+
+// readInfo reads the holder of a lockfile, nil when it cannot be read
+func readInfo(path string) *Info {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var holder Info
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
+
+// owner identifies this run, by its CI job when there is one
+func owner(host string) string {
+	if jobURL := os.Getenv("CI_JOB_URL"); jobURL != "" {
+		return jobURL
+	}
+	if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), runID)
+	}
+
+	user := os.Getenv("USER")
+	if user == "" {
+		user = os.Getenv("USERNAME")
+	}
+	if user == "" {
+		return host
+	}
+	return user + "@" + host
+}
@@ -0,0 +1,230 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"herald/internal/config"
+	"herald/internal/git"
+)
+
+// newRepo creates a git repository with one commit in a temp directory
+func newRepo(t *testing.T, dir string) *git.Repository {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q", dir},
+		{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@localhost", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	repo, err := git.OpenRepository(dir)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+	return repo
+}
+
+// newLock creates a release lock with the given settings
+func newLock(t *testing.T, repo *git.Repository, settings config.LockConfig) *Lock {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Lock = settings
+	l, err := New(cfg, repo)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return l
+}
+
+// writeLockfile leaves a lockfile behind as if another release held it
+func writeLockfile(t *testing.T, l *Lock, holder Info) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(l.path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// exitedPID returns the PID of a process that has finished
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("git", "--version")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.ProcessState.Pid()
+}
+
+func TestLocalContention(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	repo := newRepo(t, t.TempDir())
+
+	holder := newLock(t, repo, config.LockConfig{Enabled: true, OnContention: "fail"})
+	if _, err := holder.Acquire(); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		contender := newLock(t, repo, config.LockConfig{Enabled: true, OnContention: "fail", Timeout: 60})
+		_, err := contender.Acquire()
+		var contention *ContentionError
+		if !errors.As(err, &contention) {
+			t.Fatalf("Acquire() error = %v, want a ContentionError", err)
+		}
+		if contention.Holder == nil || contention.Holder.PID != os.Getpid() {
+			t.Errorf("Holder = %+v, want this process", contention.Holder)
+		}
+	})
+
+	t.Run("wait times out", func(t *testing.T) {
+		contender := newLock(t, repo, config.LockConfig{Enabled: true, OnContention: "wait", Timeout: 0})
+		_, err := contender.Acquire()
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Fatalf("Acquire() error = %v, want a timeout", err)
+		}
+	})
+
+	t.Run("wait until released", func(t *testing.T) {
+		contender := newLock(t, repo, config.LockConfig{Enabled: true, OnContention: "wait", Timeout: 10})
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			if err := holder.Release(); err != nil {
+				t.Errorf("Release() error = %v", err)
+			}
+		}()
+
+		waited, err := contender.Acquire()
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		if !waited {
+			t.Error("Acquire() waited = false, want true")
+		}
+		if err := contender.Release(); err != nil {
+			t.Fatalf("Release() error = %v", err)
+		}
+		if _, err := os.Stat(contender.path); !os.IsNotExist(err) {
+			t.Errorf("lockfile still exists after Release(): %v", err)
+		}
+	})
+}
+
+func TestStaleTakeover(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	host, _ := os.Hostname()
+
+	tests := []struct {
+		name       string
+		holder     Info
+		staleAfter int
+		takenOver  bool
+	}{
+		{
+			name:       "older than stale_after",
+			holder:     Info{Owner: "ci", Host: "other-host", PID: 1, Time: time.Now().Add(-2 * time.Hour)},
+			staleAfter: 3600,
+			takenOver:  true,
+		},
+		{
+			name:       "younger than stale_after",
+			holder:     Info{Owner: "ci", Host: "other-host", PID: 1, Time: time.Now().Add(-time.Minute)},
+			staleAfter: 3600,
+		},
+		{
+			name:   "stale_after disabled",
+			holder: Info{Owner: "ci", Host: "other-host", PID: 1, Time: time.Now().Add(-48 * time.Hour)},
+		},
+		{
+			name:      "exited process on this host",
+			holder:    Info{Owner: "me", Host: host, PID: exitedPID(t), Time: time.Now()},
+			takenOver: true,
+		},
+		{
+			name:   "running process on this host",
+			holder: Info{Owner: "me", Host: host, PID: os.Getppid(), Time: time.Now()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t, t.TempDir())
+			l := newLock(t, repo, config.LockConfig{Enabled: true, OnContention: "fail", StaleAfter: tt.staleAfter})
+			writeLockfile(t, l, tt.holder)
+
+			_, err := l.Acquire()
+			if tt.takenOver {
+				if err != nil {
+					t.Fatalf("Acquire() error = %v, want the stale lock taken over", err)
+				}
+				if holder := readInfo(l.path); holder == nil || holder.PID != os.Getpid() {
+					t.Errorf("lockfile holder = %+v, want this process", holder)
+				}
+				return
+			}
+
+			var contention *ContentionError
+			if !errors.As(err, &contention) {
+				t.Fatalf("Acquire() error = %v, want a ContentionError", err)
+			}
+		})
+	}
+}
+
+func TestRemoteContention(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	if output, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+
+	clone := func(name string) *git.Repository {
+		dir := filepath.Join(root, name)
+		repo := newRepo(t, dir)
+		if output, err := exec.Command("git", "-C", dir, "remote", "add", "origin", remote).CombinedOutput(); err != nil {
+			t.Fatalf("git remote add: %v\n%s", err, output)
+		}
+		return repo
+	}
+	settings := config.LockConfig{Remote: true, RemoteName: "origin", Ref: "refs/herald/lock", OnContention: "fail"}
+
+	holder := newLock(t, clone("a"), settings)
+	if _, err := holder.Acquire(); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	contender := newLock(t, clone("b"), settings)
+	_, err := contender.Acquire()
+	var contention *ContentionError
+	if !errors.As(err, &contention) {
+		t.Fatalf("Acquire() error = %v, want a ContentionError", err)
+	}
+	if contention.Holder == nil || contention.Holder.Owner != holder.info.Owner {
+		t.Errorf("Holder = %+v, want %s", contention.Holder, holder.info.Owner)
+	}
+
+	if err := holder.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := contender.Acquire(); err != nil {
+		t.Fatalf("Acquire() after Release() error = %v", err)
+	}
+	if err := contender.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"herald/internal/config"
+)
+
+// Message is the release information rendered into every target's payload
+type Message struct {
+	Project     string
+	Version     string
+	Tag         string
+	PreviousTag string
+	Date        time.Time
+	URL         string // Comparison with the previous release, when known
+	Markdown    string // Release notes without the version heading
+	Text        string // Release notes as plain text
+	Prerelease  bool
+}
+
+// Result is the outcome of notifying one target
+type Result struct {
+	Target   config.NotifyTarget
+	Attempts int
+	Err      error
+}
+
+// Notifier posts release notifications to the configured targets
+type Notifier struct {
+	config     *config.Config
+	client     *http.Client
+	retryDelay time.Duration // Delay before the first retry, doubled for every further retry
+}
+
+// NewNotifier creates a new release notifier
+func NewNotifier(cfg *config.Config) *Notifier {
+	timeout := time.Duration(cfg.Notify.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Notifier{
+		config:     cfg,
+		client:     &http.Client{Timeout: timeout},
+		retryDelay: time.Second,
+	}
+}
+
+// SetRetryDelay changes the delay before the first retry, which doubles for every further retry
+func (n *Notifier) SetRetryDelay(delay time.Duration) {
+	n.retryDelay = delay
+}
+
+// Notify posts the message to every target. A failing target does not stop the others.
+func (n *Notifier) Notify(ctx context.Context, message *Message) []Result {
+	var results []Result
+	for _, target := range n.config.Notify.Targets {
+		attempts, err := n.send(ctx, target, message)
+		results = append(results, Result{Target: target, Attempts: attempts, Err: err})
+	}
+	return results
+}
+
+// DryRun renders the payload of every target without sending anything
+func (n *Notifier) DryRun(message *Message) (string, error) {
+	var builder strings.Builder
+	for _, target := range n.config.Notify.Targets {
+		payload, err := Payload(target.Type, message)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(fmt.Sprintf("--- %s (%s) -> %s\n", targetName(target), target.Type, redactURL(os.ExpandEnv(target.URL))))
+		builder.WriteString(string(payload))
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
+}
+
+// send posts the payload to a target, retrying network errors, 429 and 5xx responses
+func (n *Notifier) send(ctx context.Context, target config.NotifyTarget, message *Message) (int, error) {
+	payload, err := Payload(target.Type, message)
+	if err != nil {
+		return 0, err
+	}
+
+	targetURL := os.ExpandEnv(target.URL)
+	if targetURL == "" {
+		return 0, fmt.Errorf("url of %s is empty after expanding environment variables", targetName(target))
+	}
+
+	delay := n.retryDelay
+	attempts := 0
+	for {
+		attempts++
+		retry, err := n.post(ctx, target, targetURL, payload)
+		if err == nil {
+			return attempts, nil
+		}
+		if !retry || attempts > n.config.Notify.Retries {
+			return attempts, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post sends a single request and reports whether a failure is worth retrying
+func (n *Notifier) post(ctx context.Context, target config.NotifyTarget, targetURL string, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "herald")
+	for name, value := range target.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("notification rejected with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// targetName returns the display name of a target
+func targetName(target config.NotifyTarget) string {
+	if target.Name != "" {
+		return target.Name
+	}
+	return target.Type
+}
+
+// TargetName returns the display name of a target
+func (r Result) TargetName() string {
+	return targetName(r.Target)
+}
+
+// redactURL hides the path and query of a webhook URL, which usually contain the secret
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return "(empty url, check the environment variables)"
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "(invalid url)"
+	}
+	return fmt.Sprintf("%s://%s/...", parsed.Scheme, parsed.Host)
+}
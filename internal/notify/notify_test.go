@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"herald/internal/config"
+)
+
+// testMessage is a release message with notes, a comparison link and a date
+func testMessage() *Message {
+	return &Message{
+		Project:     "herald",
+		Version:     "v1.2.0",
+		Tag:         "v1.2.0",
+		PreviousTag: "v1.1.0",
+		Date:        time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+		URL:         "https://github.com/jjojo/herald/compare/v1.1.0...v1.2.0",
+		Markdown:    "### Features\n\n* **api:** add export (abc1234)",
+		Text:        "Features\n\n- api: add export (abc1234)",
+	}
+}
+
+// newTestNotifier creates a notifier for one target that retries without delay
+func newTestNotifier(targetType, targetURL string, retries int) *Notifier {
+	cfg := config.DefaultConfig()
+	cfg.Notify.Retries = retries
+	cfg.Notify.Targets = []config.NotifyTarget{{Type: targetType, URL: targetURL}}
+
+	notifier := NewNotifier(cfg)
+	notifier.SetRetryDelay(0)
+	return notifier
+}
+
+func TestPayloadShapes(t *testing.T) {
+	tests := []struct {
+		targetType string
+		check      func(t *testing.T, payload map[string]interface{})
+	}{
+		{
+			targetType: "slack",
+			check: func(t *testing.T, payload map[string]interface{}) {
+				if payload["text"] != "herald v1.2.0 released" {
+					t.Errorf("text = %v", payload["text"])
+				}
+				blocks, _ := payload["blocks"].([]interface{})
+				if len(blocks) != 3 {
+					t.Fatalf("got %d blocks, want header, section and context", len(blocks))
+				}
+				for i, want := range []string{"header", "section", "context"} {
+					if blocks[i].(map[string]interface{})["type"] != want {
+						t.Errorf("blocks[%d].type = %v, want %s", i, blocks[i].(map[string]interface{})["type"], want)
+					}
+				}
+				section := blocks[1].(map[string]interface{})["text"].(map[string]interface{})
+				if section["type"] != "mrkdwn" || section["text"] != "*Features*\n\n• *api:* add export (abc1234)" {
+					t.Errorf("section text = %v", section)
+				}
+			},
+		},
+		{
+			targetType: "discord",
+			check: func(t *testing.T, payload map[string]interface{}) {
+				embeds, _ := payload["embeds"].([]interface{})
+				if payload["content"] != "herald v1.2.0 released" || len(embeds) != 1 {
+					t.Fatalf("payload = %v", payload)
+				}
+				embed := embeds[0].(map[string]interface{})
+				if embed["description"] != testMessage().Markdown || embed["url"] != testMessage().URL || embed["timestamp"] != "2025-01-15T12:00:00Z" {
+					t.Errorf("embed = %v", embed)
+				}
+			},
+		},
+		{
+			targetType: "teams",
+			check: func(t *testing.T, payload map[string]interface{}) {
+				if payload["@type"] != "MessageCard" || payload["title"] != "herald v1.2.0 released" {
+					t.Errorf("card = %v", payload)
+				}
+				if payload["text"] != "**Features**\n\n* **api:** add export (abc1234)" {
+					t.Errorf("text = %v", payload["text"])
+				}
+				actions, _ := payload["potentialAction"].([]interface{})
+				if len(actions) != 1 || actions[0].(map[string]interface{})["@type"] != "OpenUri" {
+					t.Errorf("potentialAction = %v", payload["potentialAction"])
+				}
+			},
+		},
+		{
+			targetType: "webhook",
+			check: func(t *testing.T, payload map[string]interface{}) {
+				want := map[string]interface{}{
+					"event":        "release",
+					"project":      "herald",
+					"version":      "v1.2.0",
+					"tag":          "v1.2.0",
+					"previous_tag": "v1.1.0",
+					"prerelease":   false,
+					"url":          testMessage().URL,
+					"notes":        testMessage().Markdown,
+					"notes_text":   testMessage().Text,
+					"date":         "2025-01-15",
+				}
+				for key, value := range want {
+					if payload[key] != value {
+						t.Errorf("%s = %v, want %v", key, payload[key], value)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.targetType, func(t *testing.T) {
+			var received []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+				}
+				received, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			results := newTestNotifier(tt.targetType, server.URL+"/hook", 0).Notify(context.Background(), testMessage())
+			if len(results) != 1 || results[0].Err != nil {
+				t.Fatalf("Notify() = %+v", results)
+			}
+
+			var payload map[string]interface{}
+			if err := json.Unmarshal(received, &payload); err != nil {
+				t.Fatalf("payload is not JSON: %v\n%s", err, received)
+			}
+			tt.check(t, payload)
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int // Responses in order, the last one repeats
+		retries  int
+		attempts int
+		failed   bool
+	}{
+		{name: "429 is retried", statuses: []int{429, 429, 200}, retries: 3, attempts: 3},
+		{name: "5xx is retried", statuses: []int{500, 503, 200}, retries: 3, attempts: 3},
+		{name: "retries run out", statuses: []int{502}, retries: 2, attempts: 3, failed: true},
+		{name: "400 is not retried", statuses: []int{400, 200}, retries: 3, attempts: 1, failed: true},
+		{name: "404 is not retried", statuses: []int{404, 200}, retries: 3, attempts: 1, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&requests, 1))
+				status := tt.statuses[len(tt.statuses)-1]
+				if n <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			results := newTestNotifier("webhook", server.URL, tt.retries).Notify(context.Background(), testMessage())
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			if results[0].Attempts != tt.attempts || int(atomic.LoadInt32(&requests)) != tt.attempts {
+				t.Errorf("attempts = %d, requests = %d, want %d", results[0].Attempts, requests, tt.attempts)
+			}
+			if (results[0].Err != nil) != tt.failed {
+				t.Errorf("error = %v, want failure %v", results[0].Err, tt.failed)
+			}
+		})
+	}
+}
+
+func TestDryRunRedactsURLs(t *testing.T) {
+	t.Setenv("HERALD_TEST_SLACK_WEBHOOK", "https://hooks.slack.com/services/T000/B000/secret")
+
+	cfg := config.DefaultConfig()
+	cfg.Notify.Targets = []config.NotifyTarget{
+		{Name: "team", Type: "slack", URL: "${HERALD_TEST_SLACK_WEBHOOK}"},
+		{Type: "webhook", URL: "https://example.com/hook?token=secret"},
+		{Type: "discord", URL: "${HERALD_TEST_UNSET_WEBHOOK}"},
+	}
+
+	output, err := NewNotifier(cfg).DryRun(testMessage())
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+	if strings.Contains(output, "secret") {
+		t.Errorf("dry run output leaks the webhook secret:\n%s", output)
+	}
+	for _, want := range []string{
+		"--- team (slack) -> https://hooks.slack.com/...",
+		"--- webhook (webhook) -> https://example.com/...",
+		"--- discord (discord) -> (empty url, check the environment variables)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dry run output is missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Platform limits on message sizes
+const (
+	slackTextLimit          = 3000
+	discordDescriptionLimit = 4096
+)
+
+var (
+	headingPattern = regexp.MustCompile(`(?m)^#{1,6} (.+)$`)
+	boldPattern    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	bulletPattern  = regexp.MustCompile(`(?m)^(\s*)\* `)
+)
+
+// Payload renders the message in the JSON format of a target type
+func Payload(targetType string, message *Message) ([]byte, error) {
+	var payload interface{}
+
+	switch strings.ToLower(targetType) {
+	case "slack":
+		payload = slackPayload(message)
+	case "discord":
+		payload = discordPayload(message)
+	case "teams":
+		payload = teamsPayload(message)
+	case "webhook":
+		payload = webhookPayload(message)
+	default:
+		return nil, fmt.Errorf("unsupported notification type '%s'", targetType)
+	}
+
+	// Slack links use angle brackets, keep them readable
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", targetType, err)
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
+
+// title returns the headline of a release message
+func title(message *Message) string {
+	headline := "Released " + message.Version
+	if message.Project != "" {
+		headline = message.Project + " " + message.Version + " released"
+	}
+	if message.Prerelease {
+		headline += " (prerelease)"
+	}
+	return headline
+}
+
+// slackPayload renders an incoming webhook message with Block Kit
+func slackPayload(message *Message) map[string]interface{} {
+	notes := truncate(toSlackMarkdown(message.Markdown), slackTextLimit)
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": title(message)},
+		},
+	}
+	if notes != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": notes},
+		})
+	}
+	if message.URL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type":     "context",
+			"elements": []map[string]string{{"type": "mrkdwn", "text": fmt.Sprintf("<%s|Compare %s...%s>", message.URL, message.PreviousTag, message.Tag)}},
+		})
+	}
+
+	return map[string]interface{}{
+		"text":   title(message),
+		"blocks": blocks,
+	}
+}
+
+// discordPayload renders a webhook message with a single embed
+func discordPayload(message *Message) map[string]interface{} {
+	embed := map[string]interface{}{
+		"title":       title(message),
+		"description": truncate(message.Markdown, discordDescriptionLimit),
+	}
+	if message.URL != "" {
+		embed["url"] = message.URL
+	}
+	if !message.Date.IsZero() {
+		embed["timestamp"] = message.Date.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	return map[string]interface{}{
+		"content": title(message),
+		"embeds":  []interface{}{embed},
+	}
+}
+
+// teamsPayload renders an incoming webhook MessageCard
+func teamsPayload(message *Message) map[string]interface{} {
+	card := map[string]interface{}{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  title(message),
+		"title":    title(message),
+		"text":     headingPattern.ReplaceAllString(message.Markdown, "**$1**"),
+	}
+	if message.URL != "" {
+		card["potentialAction"] = []map[string]interface{}{
+			{
+				"@type":   "OpenUri",
+				"name":    "View changes",
+				"targets": []map[string]string{{"os": "default", "uri": message.URL}},
+			},
+		}
+	}
+	return card
+}
+
+// webhookPayload renders the generic JSON payload
+func webhookPayload(message *Message) map[string]interface{} {
+	payload := map[string]interface{}{
+		"event":        "release",
+		"project":      message.Project,
+		"version":      message.Version,
+		"tag":          message.Tag,
+		"previous_tag": message.PreviousTag,
+		"prerelease":   message.Prerelease,
+		"url":          message.URL,
+		"notes":        message.Markdown,
+		"notes_text":   message.Text,
+	}
+	if !message.Date.IsZero() {
+		payload["date"] = message.Date.Format("2006-01-02")
+	}
+	return payload
+}
+
+// toSlackMarkdown converts changelog markdown to Slack mrkdwn
+func toSlackMarkdown(markdown string) string {
+	text := headingPattern.ReplaceAllString(markdown, "*$1*")
+	text = boldPattern.ReplaceAllString(text, "*$1*")
+	text = linkPattern.ReplaceAllString(text, "<$2|$1>")
+	return bulletPattern.ReplaceAllString(text, "$1• ")
+}
+
+// truncate shortens text to a maximum number of characters
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
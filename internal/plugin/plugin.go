@@ -0,0 +1,299 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"herald/internal/commits"
+	"herald/internal/config"
+)
+
+// ProtocolVersion is the plugin protocol herald speaks
+const ProtocolVersion = 1
+
+// supportedProtocols are the protocol versions herald accepts in responses
+var supportedProtocols = []int{1}
+
+// Lifecycle points plugins are invoked at
+const (
+	EventVersion  = "version"  // The next version is calculated, plugins may change it or veto
+	EventNotes    = "notes"    // The release notes are generated, plugins may add notes or veto
+	EventReleased = "released" // The release is published, responses are ignored
+)
+
+// defaultTimeout is the time a plugin gets per invocation when none is configured
+const defaultTimeout = 30 * time.Second
+
+// Request is the JSON document written to a plugin's stdin
+type Request struct {
+	Protocol           int           `json:"protocol"`
+	SupportedProtocols []int         `json:"supported_protocols"`
+	Event              string        `json:"event"`
+	DryRun             bool          `json:"dry_run"`
+	Config             RequestConfig `json:"config"`
+	PreviousVersion    string        `json:"previous_version,omitempty"`
+	Version            string        `json:"version"`
+	Bump               string        `json:"bump"`
+	Commits            []Commit      `json:"commits"`
+	Notes              string        `json:"notes,omitempty"` // Markdown release notes, from the notes event on
+}
+
+// RequestConfig is the part of the configuration sent to plugins. Publisher and
+// notification settings, which carry credentials, are never sent.
+type RequestConfig struct {
+	Version    RequestVersionConfig    `json:"version"`
+	Repository RequestRepositoryConfig `json:"repository"`
+	Changelog  RequestChangelogConfig  `json:"changelog"`
+}
+
+// RequestVersionConfig is the version configuration sent to plugins
+type RequestVersionConfig struct {
+	Initial string `json:"initial"`
+	Prefix  string `json:"prefix"`
+}
+
+// RequestRepositoryConfig is the repository configuration sent to plugins
+type RequestRepositoryConfig struct {
+	URL      string `json:"url,omitempty"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// RequestChangelogConfig is the changelog configuration sent to plugins
+type RequestChangelogConfig struct {
+	File    string `json:"file"`
+	GroupBy string `json:"group_by"`
+}
+
+// Commit is a release commit as seen by plugins
+type Commit struct {
+	Hash        string `json:"hash"`
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Body        string `json:"body,omitempty"`
+	Breaking    bool   `json:"breaking"`
+	Author      string `json:"author,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// Response is the JSON document a plugin writes to stdout. Empty output means no changes.
+type Response struct {
+	Protocol int    `json:"protocol"`          // Protocol the plugin speaks, ProtocolVersion when omitted
+	Version  string `json:"version,omitempty"` // Replaces the next version, only at the version event
+	Notes    string `json:"notes,omitempty"`   // Markdown added to the release notes
+	Veto     string `json:"veto,omitempty"`    // Stops the release with this reason
+}
+
+// Outcome is the combined effect of the plugins invoked at an event
+type Outcome struct {
+	Version string   // New version, empty when no plugin changed it
+	Notes   []string // Notes added by the plugins, in order
+}
+
+// VetoError reports a plugin stopping the release
+type VetoError struct {
+	Plugin string
+	Reason string
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("release vetoed by plugin %s: %s", e.Plugin, e.Reason)
+}
+
+// Runner invokes the configured plugins
+type Runner struct {
+	config *config.Config
+}
+
+// NewRunner creates a plugin runner
+func NewRunner(cfg *config.Config) *Runner {
+	return &Runner{config: cfg}
+}
+
+// NewRequest builds the request for an event
+func NewRequest(event, previousVersion, nextVersion string, bump commits.BumpType, conventionalCommits []*commits.ConventionalCommit, dryRun bool) *Request {
+	request := &Request{
+		Protocol:           ProtocolVersion,
+		SupportedProtocols: supportedProtocols,
+		Event:              event,
+		DryRun:             dryRun,
+		PreviousVersion:    previousVersion,
+		Version:            nextVersion,
+		Bump:               bump.String(),
+		Commits:            []Commit{},
+	}
+
+	for _, commit := range conventionalCommits {
+		entry := Commit{
+			Type:        commit.Type,
+			Scope:       commit.Scope,
+			Description: commit.Description,
+			Body:        commit.Body,
+			Breaking:    commit.IsBreakingChange,
+		}
+		if commit.Original != nil {
+			entry.Hash = commit.Original.Hash
+			entry.Author = commit.Original.Author
+			entry.Email = commit.Original.Email
+		}
+		request.Commits = append(request.Commits, entry)
+	}
+
+	return request
+}
+
+// Run invokes the plugins subscribed to the request's event, in configuration order.
+// A plugin sees the version set by the plugins before it. A veto stops the remaining plugins.
+func (r *Runner) Run(ctx context.Context, request *Request) (*Outcome, error) {
+	outcome := &Outcome{}
+
+	request.Config = r.requestConfig()
+
+	for _, pluginConfig := range r.config.Plugins {
+		if !subscribed(pluginConfig, request.Event) {
+			continue
+		}
+
+		response, err := r.invoke(ctx, pluginConfig, request)
+		if err != nil {
+			return nil, err
+		}
+		if response == nil || request.Event == EventReleased {
+			continue
+		}
+
+		name := pluginName(pluginConfig)
+		if response.Veto != "" {
+			return nil, &VetoError{Plugin: name, Reason: response.Veto}
+		}
+		if response.Version != "" && response.Version != request.Version {
+			if request.Event != EventVersion {
+				return nil, fmt.Errorf("plugin %s cannot change the version at the %s event", name, request.Event)
+			}
+			outcome.Version = response.Version
+			request.Version = response.Version
+		}
+		if notes := strings.TrimSpace(response.Notes); notes != "" {
+			outcome.Notes = append(outcome.Notes, notes)
+		}
+	}
+
+	return outcome, nil
+}
+
+// invoke runs one plugin and decodes its response, nil when it printed nothing
+func (r *Runner) invoke(ctx context.Context, pluginConfig config.PluginConfig, request *Request) (*Response, error) {
+	name := pluginName(pluginConfig)
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	timeout := time.Duration(pluginConfig.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, pluginConfig.Command, pluginConfig.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr // Plugin logs are shown to the user
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("HERALD_PLUGIN_PROTOCOL=%d", ProtocolVersion),
+		"HERALD_PLUGIN_EVENT="+request.Event,
+	)
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin %s timed out after %s", name, timeout)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", name, err)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var response Response
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid JSON: %w", name, err)
+	}
+	if response.Protocol == 0 {
+		response.Protocol = ProtocolVersion
+	}
+	if !supportsProtocol(response.Protocol) {
+		return nil, fmt.Errorf("plugin %s speaks protocol %d, herald supports %s", name, response.Protocol, formatProtocols())
+	}
+
+	return &response, nil
+}
+
+// requestConfig picks the documented settings sent to plugins from the configuration
+func (r *Runner) requestConfig() RequestConfig {
+	return RequestConfig{
+		Version: RequestVersionConfig{
+			Initial: r.config.Version.Initial,
+			Prefix:  r.config.Version.Prefix,
+		},
+		Repository: RequestRepositoryConfig{
+			URL:      r.config.Repository.URL,
+			Provider: r.config.Repository.Provider,
+		},
+		Changelog: RequestChangelogConfig{
+			File:    r.config.Changelog.File,
+			GroupBy: r.config.Changelog.GroupBy,
+		},
+	}
+}
+
+// subscribed reports whether a plugin is invoked at an event
+func subscribed(pluginConfig config.PluginConfig, event string) bool {
+	if len(pluginConfig.Events) == 0 {
+		return true
+	}
+	for _, subscribedEvent := range pluginConfig.Events {
+		if subscribedEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginName returns the display name of a plugin
+func pluginName(pluginConfig config.PluginConfig) string {
+	if pluginConfig.Name != "" {
+		return pluginConfig.Name
+	}
+	return pluginConfig.Command
+}
+
+// supportsProtocol reports whether herald accepts a protocol version
+func supportsProtocol(protocol int) bool {
+	for _, supported := range supportedProtocols {
+		if supported == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// formatProtocols lists the supported protocol versions
+func formatProtocols() string {
+	var versions []string
+	for _, supported := range supportedProtocols {
+		versions = append(versions, fmt.Sprintf("%d", supported))
+	}
+	return strings.Join(versions, ", ")
+}
@@ -0,0 +1,98 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiClient sends authenticated requests to a hosting provider's REST API
+type apiClient struct {
+	client  *http.Client
+	headers map[string]string // Added to every request, e.g. the token
+}
+
+// newAPIClient creates an API client sending the given headers with every request
+func newAPIClient(headers map[string]string) *apiClient {
+	return &apiClient{
+		// Generous timeout, asset uploads can be large
+		client:  &http.Client{Timeout: 10 * time.Minute},
+		headers: headers,
+	}
+}
+
+// do sends a JSON request and decodes the response into out, when given
+func (c *apiClient) do(ctx context.Context, method, requestURL string, body interface{}, out interface{}) error {
+	var data []byte
+	contentType := ""
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		data = encoded
+		contentType = "application/json"
+	}
+	return c.upload(ctx, method, requestURL, contentType, data, out)
+}
+
+// upload sends a raw request body and decodes the JSON response into out, when given
+func (c *apiClient) upload(ctx context.Context, method, requestURL, contentType string, data []byte, out interface{}) error {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "herald")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &apiError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// apiError is an unsuccessful API response
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// isNotFound reports whether an API call failed because the resource does not exist
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
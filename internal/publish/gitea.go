@@ -0,0 +1,161 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"strings"
+
+	"herald/internal/changelog"
+	"herald/internal/config"
+)
+
+// Gitea creates and updates Gitea and Forgejo releases through the REST API
+type Gitea struct {
+	config  config.GiteaPublishConfig
+	baseURL string
+	owner   string
+	repo    string
+}
+
+// NewGitea creates a Gitea or Forgejo releases publisher. The instance,
+// owner and repository default to the ones of repository.url.
+func NewGitea(cfg *config.Config) *Gitea {
+	giteaConfig := cfg.Publish.Gitea
+
+	baseURL := giteaConfig.URL
+	if baseURL == "" {
+		baseURL = instanceURL(cfg.Repository.URL)
+	}
+
+	owner, repo := giteaConfig.Owner, giteaConfig.Repo
+	if owner == "" || repo == "" {
+		detectedOwner, detectedRepo := ownerAndRepo(cfg.Repository.URL)
+		if owner == "" {
+			owner = detectedOwner
+		}
+		if repo == "" {
+			repo = detectedRepo
+		}
+	}
+
+	return &Gitea{
+		config:  giteaConfig,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		owner:   owner,
+		repo:    repo,
+	}
+}
+
+// Name returns the name of the publisher
+func (g *Gitea) Name() string {
+	return "gitea"
+}
+
+// Validate checks the publish.gitea settings
+func (g *Gitea) Validate(cfg *config.Config) error {
+	if g.baseURL == "" {
+		return fmt.Errorf("cannot determine the Gitea instance, set publish.gitea.url")
+	}
+	if err := validateURL("gitea.url", g.baseURL); err != nil {
+		return err
+	}
+	if cfg.Publish.Gitea.TokenEnv == "" {
+		return fmt.Errorf("publish.gitea.token_env cannot be empty")
+	}
+	if g.owner == "" || g.repo == "" {
+		return fmt.Errorf("cannot determine the Gitea repository, set publish.gitea.owner and publish.gitea.repo")
+	}
+	return validateAssets("gitea", cfg.Publish.Gitea.Assets)
+}
+
+// DryRun describes the release that would be published
+func (g *Gitea) DryRun(release *Release) (string, error) {
+	assets, err := matchAssets(g.config.Assets)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Would publish Gitea release %s to %s/%s (%s)\n", release.Tag, g.owner, g.repo, g.apiURL()))
+	builder.WriteString(fmt.Sprintf("- Prerelease: %t, draft: %t\n", release.Prerelease, g.config.Draft))
+	for _, asset := range assets {
+		builder.WriteString(fmt.Sprintf("- Asset: %s\n", asset))
+	}
+	return builder.String(), nil
+}
+
+// Publish creates the release of the tag, or updates it when it already exists,
+// and uploads the assets. It returns the web URL of the release.
+func (g *Gitea) Publish(ctx context.Context, release *Release) (string, error) {
+	token := os.Getenv(g.config.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("no Gitea token found in $%s", g.config.TokenEnv)
+	}
+
+	releases := &releaseAPI{
+		host: "Gitea",
+		headers: map[string]string{
+			"Authorization": "token " + token,
+			"Accept":        "application/json",
+		},
+		listURL: func(page int) string {
+			return g.repoURL(fmt.Sprintf("releases?limit=%d&page=%d", releasesPageSize, page))
+		},
+		createURL: g.repoURL("releases"),
+		updateURL: func(existing *releaseResource) string {
+			return g.repoURL(fmt.Sprintf("releases/%d", existing.ID))
+		},
+		deleteAssetURL: func(published *releaseResource, asset releaseAsset) string {
+			return g.repoURL(fmt.Sprintf("releases/%d/assets/%d", published.ID, asset.ID))
+		},
+		uploadURL: func(published *releaseResource, name string) string {
+			return g.repoURL(fmt.Sprintf("releases/%d/assets?name=%s", published.ID, url.QueryEscape(name)))
+		},
+		encodeAsset: multipartAsset,
+	}
+	return releases.publish(ctx, release, g.config.Draft, g.config.Assets)
+}
+
+// multipartAsset sends an asset as a multipart form with an "attachment" file field
+func multipartAsset(name string, data []byte) (string, []byte, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("attachment", name)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", nil, err
+	}
+	if err := form.Close(); err != nil {
+		return "", nil, err
+	}
+	return form.FormDataContentType(), body.Bytes(), nil
+}
+
+// apiURL returns the base URL of the REST API
+func (g *Gitea) apiURL() string {
+	return g.baseURL + "/api/v1"
+}
+
+// repoURL returns the API URL of a repository resource
+func (g *Gitea) repoURL(resource string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/%s", g.apiURL(), url.PathEscape(g.owner), url.PathEscape(g.repo), resource)
+}
+
+// instanceURL returns the scheme and host of the instance serving a repository.
+// HTTP remotes keep their port, SSH ports do not belong to the web interface.
+func instanceURL(repoURL string) string {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		parsed, err = url.Parse(changelog.NormalizeRemoteURL(repoURL))
+	}
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+}
@@ -0,0 +1,141 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"herald/internal/config"
+)
+
+// GitHub creates and updates GitHub Releases through the REST API
+type GitHub struct {
+	config config.GitHubPublishConfig
+	apiURL string
+	owner  string
+	repo   string
+}
+
+// NewGitHub creates a GitHub Releases publisher. The owner and repository
+// default to the ones of repository.url.
+func NewGitHub(cfg *config.Config) *GitHub {
+	githubConfig := cfg.Publish.GitHub
+
+	owner, repo := githubConfig.Owner, githubConfig.Repo
+	if owner == "" || repo == "" {
+		detectedOwner, detectedRepo := ownerAndRepo(cfg.Repository.URL)
+		if owner == "" {
+			owner = detectedOwner
+		}
+		if repo == "" {
+			repo = detectedRepo
+		}
+	}
+
+	return &GitHub{
+		config: githubConfig,
+		apiURL: strings.TrimSuffix(githubConfig.APIURL, "/"),
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+// Name returns the name of the publisher
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+// Validate checks the publish.github settings
+func (g *GitHub) Validate(cfg *config.Config) error {
+	if err := validateURL("github.api_url", cfg.Publish.GitHub.APIURL); err != nil {
+		return err
+	}
+	if cfg.Publish.GitHub.TokenEnv == "" {
+		return fmt.Errorf("publish.github.token_env cannot be empty")
+	}
+	if g.owner == "" || g.repo == "" {
+		return fmt.Errorf("cannot determine the GitHub repository, set publish.github.owner and publish.github.repo")
+	}
+	return validateAssets("github", cfg.Publish.GitHub.Assets)
+}
+
+// DryRun describes the release that would be published
+func (g *GitHub) DryRun(release *Release) (string, error) {
+	assets, err := matchAssets(g.config.Assets)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Would publish GitHub release %s to %s/%s (%s)\n", release.Tag, g.owner, g.repo, g.apiURL))
+	builder.WriteString(fmt.Sprintf("- Prerelease: %t, draft: %t\n", release.Prerelease, g.config.Draft))
+	for _, asset := range assets {
+		builder.WriteString(fmt.Sprintf("- Asset: %s\n", asset))
+	}
+	return builder.String(), nil
+}
+
+// Publish creates the release of the tag, or updates it when it already exists,
+// and uploads the assets. It returns the web URL of the release.
+func (g *GitHub) Publish(ctx context.Context, release *Release) (string, error) {
+	token := os.Getenv(g.config.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("no GitHub token found in $%s", g.config.TokenEnv)
+	}
+
+	releases := &releaseAPI{
+		host: "GitHub",
+		headers: map[string]string{
+			"Authorization":        "Bearer " + token,
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		},
+		listURL: func(page int) string {
+			return g.repoURL(fmt.Sprintf("releases?per_page=%d&page=%d", releasesPageSize, page))
+		},
+		createURL: g.repoURL("releases"),
+		updateURL: func(existing *releaseResource) string {
+			return g.repoURL(fmt.Sprintf("releases/%d", existing.ID))
+		},
+		deleteAssetURL: func(_ *releaseResource, asset releaseAsset) string {
+			return g.repoURL(fmt.Sprintf("releases/assets/%d", asset.ID))
+		},
+		uploadURL: func(published *releaseResource, name string) string {
+			// The upload URL is a URI template such as ".../assets{?name,label}"
+			uploadURL := published.UploadURL
+			if i := strings.Index(uploadURL, "{"); i >= 0 {
+				uploadURL = uploadURL[:i]
+			}
+			return uploadURL + "?name=" + url.QueryEscape(name)
+		},
+		encodeAsset: rawAsset,
+	}
+	return releases.publish(ctx, release, g.config.Draft, g.config.Assets)
+}
+
+// rawAsset sends an asset as the request body, typed by its file extension
+func rawAsset(name string, data []byte) (string, []byte, error) {
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return contentType, data, nil
+}
+
+// repoURL returns the API URL of a repository resource
+func (g *GitHub) repoURL(resource string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/%s", g.apiURL, url.PathEscape(g.owner), url.PathEscape(g.repo), resource)
+}
+
+// ownerAndRepo returns the owner and name of a repository from its URL
+func ownerAndRepo(repoURL string) (string, string) {
+	parts := strings.Split(repositoryPath(repoURL), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
@@ -0,0 +1,274 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"herald/internal/config"
+)
+
+// GitLab creates and updates GitLab releases through the REST API
+type GitLab struct {
+	config      config.GitLabPublishConfig
+	apiURL      string
+	projectID   string
+	packageName string
+}
+
+// gitlabRelease is the part of the GitLab release resource herald uses
+type gitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  struct {
+		Links []gitlabLink `json:"links"`
+	} `json:"assets"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// gitlabLink is an asset link of a release
+type gitlabLink struct {
+	ID       int64  `json:"id,omitempty"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	LinkType string `json:"link_type,omitempty"`
+}
+
+// gitlabReleaseRequest is the body of the create and update release requests
+type gitlabReleaseRequest struct {
+	TagName     string              `json:"tag_name,omitempty"`
+	Ref         string              `json:"ref,omitempty"` // Commit the tag is created at when it does not exist
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Milestones  []string            `json:"milestones,omitempty"`
+	Assets      *gitlabAssetRequest `json:"assets,omitempty"`
+}
+
+// gitlabAssetRequest holds the asset links of a new release
+type gitlabAssetRequest struct {
+	Links []gitlabLink `json:"links"`
+}
+
+// NewGitLab creates a GitLab releases publisher. The API URL and project
+// default to the GitLab CI variables, then to gitlab.com and repository.url.
+func NewGitLab(cfg *config.Config) *GitLab {
+	gitlabConfig := cfg.Publish.GitLab
+
+	apiURL := firstNonEmpty(gitlabConfig.APIURL, os.Getenv("CI_API_V4_URL"), "https://gitlab.com/api/v4")
+
+	projectPath := repositoryPath(cfg.Repository.URL)
+	projectID := firstNonEmpty(gitlabConfig.ProjectID, os.Getenv("CI_PROJECT_ID"), projectPath)
+
+	packageName := gitlabConfig.PackageName
+	if packageName == "" && projectPath != "" {
+		packageName = path.Base(projectPath)
+	}
+	packageName = firstNonEmpty(packageName, os.Getenv("CI_PROJECT_NAME"), "release")
+
+	return &GitLab{
+		config:      gitlabConfig,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		projectID:   projectID,
+		packageName: packageName,
+	}
+}
+
+// Name returns the name of the publisher
+func (g *GitLab) Name() string {
+	return "gitlab"
+}
+
+// Validate checks the publish.gitlab settings
+func (g *GitLab) Validate(cfg *config.Config) error {
+	if err := validateURL("gitlab.api_url", g.apiURL); err != nil {
+		return err
+	}
+	if g.projectID == "" {
+		return fmt.Errorf("cannot determine the GitLab project, set publish.gitlab.project_id")
+	}
+	return validateAssets("gitlab", cfg.Publish.GitLab.Assets)
+}
+
+// DryRun describes the release that would be published
+func (g *GitLab) DryRun(release *Release) (string, error) {
+	assets, err := matchAssets(g.config.Assets)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Would publish GitLab release %s to project %s (%s)\n", release.Tag, g.projectID, g.apiURL))
+	for _, milestone := range g.milestones(release) {
+		builder.WriteString(fmt.Sprintf("- Milestone: %s\n", milestone))
+	}
+	for _, asset := range assets {
+		builder.WriteString(fmt.Sprintf("- Asset: %s -> %s\n", asset, g.packageURL(release, filepath.Base(asset))))
+	}
+	return builder.String(), nil
+}
+
+// Publish uploads the assets to the generic package registry, then creates
+// the release of the tag with links to them, or updates it when it already
+// exists. It returns the web URL of the release.
+func (g *GitLab) Publish(ctx context.Context, release *Release) (string, error) {
+	headers, err := g.authHeaders()
+	if err != nil {
+		return "", err
+	}
+	api := newAPIClient(headers)
+
+	assets, err := matchAssets(g.config.Assets)
+	if err != nil {
+		return "", err
+	}
+
+	var links []gitlabLink
+	for _, asset := range assets {
+		link, err := g.uploadPackageFile(ctx, api, release, asset)
+		if err != nil {
+			return "", err
+		}
+		links = append(links, link)
+	}
+
+	existing, err := g.getRelease(ctx, api, release.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	request := gitlabReleaseRequest{
+		Name:        release.Tag,
+		Description: release.Notes,
+		Milestones:  g.milestones(release),
+	}
+
+	var published gitlabRelease
+	if existing == nil {
+		request.TagName = release.Tag
+		request.Ref = release.Commit
+		if len(links) > 0 {
+			request.Assets = &gitlabAssetRequest{Links: links}
+		}
+		if err := api.do(ctx, http.MethodPost, g.projectURL("releases"), request, &published); err != nil {
+			return "", fmt.Errorf("failed to publish GitLab release %s: %w", release.Tag, err)
+		}
+		return published.Links.Self, nil
+	}
+
+	if err := api.do(ctx, http.MethodPut, g.releaseURL(release.Tag, ""), request, &published); err != nil {
+		return "", fmt.Errorf("failed to publish GitLab release %s: %w", release.Tag, err)
+	}
+	for _, link := range links {
+		if err := g.replaceLink(ctx, api, existing, release.Tag, link); err != nil {
+			return "", err
+		}
+	}
+	return published.Links.Self, nil
+}
+
+// authHeaders returns the token header, preferring the configured private token over the CI job token
+func (g *GitLab) authHeaders() (map[string]string, error) {
+	jobToken := os.Getenv("CI_JOB_TOKEN")
+	if g.config.TokenEnv != "" {
+		// Pipelines often pass the job token through the private token variable,
+		// GitLab only accepts it in the JOB-TOKEN header
+		if token := os.Getenv(g.config.TokenEnv); token != "" && token != jobToken {
+			return map[string]string{"PRIVATE-TOKEN": token}, nil
+		}
+	}
+	if jobToken != "" {
+		return map[string]string{"JOB-TOKEN": jobToken}, nil
+	}
+	return nil, fmt.Errorf("no GitLab token found in $%s or $CI_JOB_TOKEN", g.config.TokenEnv)
+}
+
+// getRelease returns the release of a tag, or nil when there is none
+func (g *GitLab) getRelease(ctx context.Context, api *apiClient, tag string) (*gitlabRelease, error) {
+	var existing gitlabRelease
+	err := api.do(ctx, http.MethodGet, g.releaseURL(tag, ""), nil, &existing)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up GitLab release %s: %w", tag, err)
+	}
+	return &existing, nil
+}
+
+// uploadPackageFile uploads an asset to the generic package registry and returns its release link
+func (g *GitLab) uploadPackageFile(ctx context.Context, api *apiClient, release *Release, file string) (gitlabLink, error) {
+	name := filepath.Base(file)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return gitlabLink{}, fmt.Errorf("failed to read asset %s: %w", file, err)
+	}
+
+	packageURL := g.packageURL(release, name)
+	if err := api.upload(ctx, http.MethodPut, packageURL, "application/octet-stream", data, nil); err != nil {
+		return gitlabLink{}, fmt.Errorf("failed to upload asset %s: %w", name, err)
+	}
+
+	return gitlabLink{Name: name, URL: packageURL, LinkType: "package"}, nil
+}
+
+// replaceLink adds an asset link to an existing release, replacing a link with the same name
+func (g *GitLab) replaceLink(ctx context.Context, api *apiClient, existing *gitlabRelease, tag string, link gitlabLink) error {
+	for _, current := range existing.Assets.Links {
+		if current.Name != link.Name {
+			continue
+		}
+		if err := api.do(ctx, http.MethodDelete, g.releaseURL(tag, fmt.Sprintf("assets/links/%d", current.ID)), nil, nil); err != nil {
+			return fmt.Errorf("failed to replace asset link %s: %w", link.Name, err)
+		}
+	}
+
+	if err := api.do(ctx, http.MethodPost, g.releaseURL(tag, "assets/links"), link, nil); err != nil {
+		return fmt.Errorf("failed to link asset %s: %w", link.Name, err)
+	}
+	return nil
+}
+
+// milestones returns the configured milestone titles for a release
+func (g *GitLab) milestones(release *Release) []string {
+	var milestones []string
+	for _, milestone := range g.config.Milestones {
+		milestones = append(milestones, strings.ReplaceAll(milestone, "{version}", release.Version))
+	}
+	return milestones
+}
+
+// projectURL returns the API URL of a project resource
+func (g *GitLab) projectURL(resource string) string {
+	return fmt.Sprintf("%s/projects/%s/%s", g.apiURL, url.PathEscape(g.projectID), resource)
+}
+
+// releaseURL returns the API URL of a release, or of one of its resources
+func (g *GitLab) releaseURL(tag, resource string) string {
+	releaseURL := g.projectURL("releases/" + url.PathEscape(tag))
+	if resource != "" {
+		releaseURL += "/" + resource
+	}
+	return releaseURL
+}
+
+// packageURL returns the generic package registry URL of a release asset
+func (g *GitLab) packageURL(release *Release, name string) string {
+	return g.projectURL(fmt.Sprintf("packages/generic/%s/%s/%s", url.PathEscape(g.packageName), url.PathEscape(release.Version), url.PathEscape(name)))
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"herald/internal/config"
+)
+
+// fakeGitLab serves the releases and generic packages API of project 42
+type fakeGitLab struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	release  map[string]interface{} // Existing release of v1.2.0, nil when there is none
+	requests []string               // "METHOD path" of every request
+	headers  []http.Header
+	bodies   map[string]map[string]interface{} // Last JSON body per request
+	packages map[string][]byte
+}
+
+func newFakeGitLab(t *testing.T, release map[string]interface{}) *fakeGitLab {
+	gitlab := &fakeGitLab{release: release, bodies: make(map[string]map[string]interface{}), packages: make(map[string][]byte)}
+	gitlab.server = httptest.NewServer(http.HandlerFunc(gitlab.serve))
+	t.Cleanup(gitlab.server.Close)
+	return gitlab
+}
+
+func (g *fakeGitLab) serve(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	request := r.Method + " " + r.URL.EscapedPath()
+	g.requests = append(g.requests, request)
+	g.headers = append(g.headers, r.Header.Clone())
+	body, _ := io.ReadAll(r.Body)
+
+	self := map[string]interface{}{"_links": map[string]string{"self": "https://gitlab.example.com/o/r/-/releases/v1.2.0"}}
+	switch {
+	case strings.HasPrefix(request, "PUT /projects/42/packages/generic/"):
+		g.packages[strings.TrimPrefix(r.URL.EscapedPath(), "/projects/42/packages/generic/")] = body
+		w.WriteHeader(http.StatusCreated)
+	case request == "GET /projects/42/releases/v1.2.0":
+		if g.release == nil {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"404 Not Found"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(g.release)
+	case request == "POST /projects/42/releases", request == "PUT /projects/42/releases/v1.2.0", request == "POST /projects/42/releases/v1.2.0/assets/links":
+		var decoded map[string]interface{}
+		json.Unmarshal(body, &decoded)
+		g.bodies[request] = decoded
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(self)
+	case strings.HasPrefix(request, "DELETE /projects/42/releases/v1.2.0/assets/links/"):
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"message":"404 Not Found"}`)
+	}
+}
+
+// newGitLabPublisher creates a GitLab publisher for the fake server
+func newGitLabPublisher(serverURL string, assets []string) *GitLab {
+	cfg := config.DefaultConfig()
+	cfg.Publish.GitLab = config.GitLabPublishConfig{
+		Enabled:     true,
+		APIURL:      serverURL,
+		ProjectID:   "42",
+		TokenEnv:    "HERALD_TEST_TOKEN",
+		PackageName: "app",
+		Assets:      assets,
+		Milestones:  []string{"{version}"},
+	}
+	return NewGitLab(cfg)
+}
+
+func TestGitLabCreatesRelease(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "secret")
+	t.Setenv("CI_JOB_TOKEN", "")
+	gitlab := newFakeGitLab(t, nil)
+	asset := writeAsset(t, "app.txt", "build output")
+
+	releaseURL, err := newGitLabPublisher(gitlab.server.URL, []string{asset}).Publish(context.Background(), testRelease())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if releaseURL != "https://gitlab.example.com/o/r/-/releases/v1.2.0" {
+		t.Errorf("Publish() = %q, want the _links.self URL", releaseURL)
+	}
+
+	want := []string{
+		"PUT /projects/42/packages/generic/app/v1.2.0/app.txt",
+		"GET /projects/42/releases/v1.2.0",
+		"POST /projects/42/releases",
+	}
+	if !slices.Equal(gitlab.requests, want) {
+		t.Errorf("requests = %q, want %q", gitlab.requests, want)
+	}
+	if string(gitlab.packages["app/v1.2.0/app.txt"]) != "build output" {
+		t.Errorf("package file = %q, want the asset content", gitlab.packages["app/v1.2.0/app.txt"])
+	}
+
+	body := gitlab.bodies["POST /projects/42/releases"]
+	if body["tag_name"] != "v1.2.0" || body["ref"] != "abc1234def" || body["description"] != testRelease().Notes {
+		t.Errorf("create request = %v", body)
+	}
+	if milestones, _ := body["milestones"].([]interface{}); len(milestones) != 1 || milestones[0] != "v1.2.0" {
+		t.Errorf("milestones = %v, want [v1.2.0]", body["milestones"])
+	}
+	links, _ := body["assets"].(map[string]interface{})["links"].([]interface{})
+	if len(links) != 1 || links[0].(map[string]interface{})["url"] != gitlab.server.URL+"/projects/42/packages/generic/app/v1.2.0/app.txt" {
+		t.Errorf("asset links = %v, want the package file", links)
+	}
+}
+
+func TestGitLabUpdatesExistingRelease(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "secret")
+	t.Setenv("CI_JOB_TOKEN", "")
+	gitlab := newFakeGitLab(t, map[string]interface{}{
+		"tag_name": "v1.2.0",
+		"assets": map[string]interface{}{"links": []map[string]interface{}{
+			{"id": 7, "name": "app.txt", "url": "https://example.com/old/app.txt"},
+			{"id": 8, "name": "checksums.txt", "url": "https://example.com/old/checksums.txt"},
+		}},
+	})
+	asset := writeAsset(t, "app.txt", "new build")
+
+	if _, err := newGitLabPublisher(gitlab.server.URL, []string{asset}).Publish(context.Background(), testRelease()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	want := []string{
+		"PUT /projects/42/packages/generic/app/v1.2.0/app.txt",
+		"GET /projects/42/releases/v1.2.0",
+		"PUT /projects/42/releases/v1.2.0",
+		"DELETE /projects/42/releases/v1.2.0/assets/links/7",
+		"POST /projects/42/releases/v1.2.0/assets/links",
+	}
+	if !slices.Equal(gitlab.requests, want) {
+		t.Errorf("requests = %q, want %q", gitlab.requests, want)
+	}
+
+	update := gitlab.bodies["PUT /projects/42/releases/v1.2.0"]
+	if update["description"] != testRelease().Notes {
+		t.Errorf("description = %v, want the new notes", update["description"])
+	}
+	if _, ok := update["tag_name"]; ok {
+		t.Errorf("update request = %v, want no tag_name", update)
+	}
+	if link := gitlab.bodies["POST /projects/42/releases/v1.2.0/assets/links"]; link["name"] != "app.txt" || link["link_type"] != "package" {
+		t.Errorf("asset link = %v, want the new app.txt package link", link)
+	}
+}
+
+func TestGitLabTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		jobToken string
+		header   string
+		value    string
+	}{
+		{name: "private token", token: "secret", header: "PRIVATE-TOKEN", value: "secret"},
+		{name: "private token preferred over the job token", token: "secret", jobToken: "job", header: "PRIVATE-TOKEN", value: "secret"},
+		{name: "job token", jobToken: "job", header: "JOB-TOKEN", value: "job"},
+		{name: "job token passed as the private token", token: "job", jobToken: "job", header: "JOB-TOKEN", value: "job"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HERALD_TEST_TOKEN", tt.token)
+			t.Setenv("CI_JOB_TOKEN", tt.jobToken)
+			gitlab := newFakeGitLab(t, nil)
+
+			if _, err := newGitLabPublisher(gitlab.server.URL, nil).Publish(context.Background(), testRelease()); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			for i, header := range gitlab.headers {
+				if got := header.Get(tt.header); got != tt.value {
+					t.Errorf("request %s sent %s %q, want %q", gitlab.requests[i], tt.header, got, tt.value)
+				}
+				for _, other := range []string{"PRIVATE-TOKEN", "JOB-TOKEN"} {
+					if other != tt.header && header.Get(other) != "" {
+						t.Errorf("request %s also sent %s", gitlab.requests[i], other)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("no token", func(t *testing.T) {
+		t.Setenv("HERALD_TEST_TOKEN", "")
+		t.Setenv("CI_JOB_TOKEN", "")
+		gitlab := newFakeGitLab(t, nil)
+
+		if _, err := newGitLabPublisher(gitlab.server.URL, nil).Publish(context.Background(), testRelease()); err == nil {
+			t.Error("Publish() error = nil, want a missing token error")
+		}
+		if len(gitlab.requests) != 0 {
+			t.Errorf("sent %d requests, want none", len(gitlab.requests))
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"herald/internal/config"
+	"herald/internal/notify"
+)
+
+// Notifier publishes a release to the notification targets under notify.targets
+type Notifier struct {
+	notifier *notify.Notifier
+	config   *config.Config
+}
+
+// NewNotifier creates the notification publisher
+func NewNotifier(cfg *config.Config) *Notifier {
+	return &Notifier{
+		notifier: notify.NewNotifier(cfg),
+		config:   cfg,
+	}
+}
+
+// Name returns the name of the publisher
+func (n *Notifier) Name() string {
+	return "notify"
+}
+
+// Validate has nothing to check, the targets are validated with the configuration
+func (n *Notifier) Validate(cfg *config.Config) error {
+	return nil
+}
+
+// DryRun renders the payload of every target
+func (n *Notifier) DryRun(release *Release) (string, error) {
+	payloads, err := n.notifier.DryRun(message(release))
+	if err != nil {
+		return "", err
+	}
+	return "Would send notifications:\n" + payloads, nil
+}
+
+// Publish posts the release to every target. Retrying notifies all targets again.
+func (n *Notifier) Publish(ctx context.Context, release *Release) (string, error) {
+	var failures []string
+	for _, result := range n.notifier.Notify(ctx, message(release)) {
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s after %d attempts: %v", result.TargetName(), result.Attempts, result.Err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return "", fmt.Errorf("%d of %d notifications failed: %s", len(failures), len(n.config.Notify.Targets), strings.Join(failures, "; "))
+	}
+	return "", nil
+}
+
+// message converts the release into a notification message
+func message(release *Release) *notify.Message {
+	return &notify.Message{
+		Project:     release.Project,
+		Version:     release.Version,
+		Tag:         release.Tag,
+		PreviousTag: release.PreviousTag,
+		Date:        release.Date,
+		URL:         release.URL,
+		Markdown:    release.Notes,
+		Text:        release.Text,
+		Prerelease:  release.Prerelease,
+	}
+}
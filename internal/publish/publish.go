@@ -0,0 +1,207 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"herald/internal/changelog"
+	"herald/internal/config"
+	"herald/internal/version"
+)
+
+// Publisher is a destination a release is published to, such as a forge or chat
+type Publisher interface {
+	// Name returns the name of the publisher, which is also its key under "publish"
+	Name() string
+	// Validate checks the publisher's settings
+	Validate(cfg *config.Config) error
+	// DryRun describes what publishing the release would do
+	DryRun(release *Release) (string, error)
+	// Publish publishes the release and returns its web URL, when there is one
+	Publish(ctx context.Context, release *Release) (string, error)
+}
+
+// registration is a publisher known to herald
+type registration struct {
+	name    string
+	enabled func(cfg *config.Config) bool
+	create  func(cfg *config.Config) Publisher
+}
+
+// registry lists the publishers in their default order: forges first,
+// so notifications can point to the published release
+var registry = []registration{
+	{
+		name:    "github",
+		enabled: func(cfg *config.Config) bool { return cfg.Publish.GitHub.Enabled },
+		create:  func(cfg *config.Config) Publisher { return NewGitHub(cfg) },
+	},
+	{
+		name:    "gitlab",
+		enabled: func(cfg *config.Config) bool { return cfg.Publish.GitLab.Enabled },
+		create:  func(cfg *config.Config) Publisher { return NewGitLab(cfg) },
+	},
+	{
+		name:    "gitea",
+		enabled: func(cfg *config.Config) bool { return cfg.Publish.Gitea.Enabled },
+		create:  func(cfg *config.Config) Publisher { return NewGitea(cfg) },
+	},
+	{
+		name:    "notify",
+		enabled: func(cfg *config.Config) bool { return len(cfg.Notify.Targets) > 0 },
+		create:  func(cfg *config.Config) Publisher { return NewNotifier(cfg) },
+	},
+}
+
+// Publishers returns the enabled and validated publishers, in the order of
+// publish.order followed by the remaining ones in their default order
+func Publishers(cfg *config.Config) ([]Publisher, error) {
+	byName := make(map[string]registration)
+	for _, entry := range registry {
+		byName[entry.name] = entry
+	}
+
+	var ordered []registration
+	seen := make(map[string]bool)
+	for _, name := range cfg.Publish.Order {
+		entry, exists := byName[strings.ToLower(name)]
+		if !exists {
+			return nil, fmt.Errorf("publish.order has unknown publisher '%s' (must be: %s)", name, strings.Join(Names(), ", "))
+		}
+		if !seen[entry.name] {
+			seen[entry.name] = true
+			ordered = append(ordered, entry)
+		}
+	}
+	for _, entry := range registry {
+		if !seen[entry.name] {
+			ordered = append(ordered, entry)
+		}
+	}
+
+	var publishers []Publisher
+	for _, entry := range ordered {
+		if !entry.enabled(cfg) {
+			continue
+		}
+		publisher := entry.create(cfg)
+		if err := publisher.Validate(cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s publisher: %w", entry.name, err)
+		}
+		publishers = append(publishers, publisher)
+	}
+
+	return publishers, nil
+}
+
+// Names returns the names of all known publishers
+func Names() []string {
+	var names []string
+	for _, entry := range registry {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+// Release is the release information handed to the publishers
+type Release struct {
+	Project     string
+	Version     string
+	Tag         string
+	PreviousTag string
+	Commit      string // Hash of the commit the tag points to
+	Date        time.Time
+	URL         string // Comparison with the previous release, when known
+	Notes       string // Markdown release notes without the version heading
+	Text        string // Release notes as plain text
+	Prerelease  bool
+}
+
+// NewRelease builds the published release from a changelog release, its notes and the commit of its tag
+func NewRelease(cfg *config.Config, release *changelog.Release, section *changelog.Section, commit string) (*Release, error) {
+	text, err := changelog.RenderNotes(section, changelog.FormatText)
+	if err != nil {
+		return nil, err
+	}
+
+	project := ""
+	if repoPath := repositoryPath(cfg.Repository.URL); repoPath != "" {
+		project = path.Base(repoPath)
+	}
+
+	return &Release{
+		Project:     project,
+		Version:     release.Version.String(),
+		Tag:         version.NewManager(cfg).FormatTagName(release.Version),
+		PreviousTag: release.PreviousTag,
+		Commit:      commit,
+		Date:        release.Date,
+		URL:         section.Link,
+		Notes:       section.Body(),
+		Text:        text,
+		Prerelease:  release.Version.IsPrerelease(),
+	}, nil
+}
+
+// validateAssets checks the asset glob patterns of a publisher
+func validateAssets(key string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("publish.%s.assets has an invalid pattern '%s': %w", key, pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateURL checks a configured base URL
+func validateURL(key, value string) error {
+	if parsed, err := url.Parse(value); err != nil || parsed.Host == "" {
+		return fmt.Errorf("publish.%s '%s' is not a valid URL", key, value)
+	}
+	return nil
+}
+
+// matchAssets expands the asset glob patterns into a sorted list of files.
+// A pattern matching nothing is an error, a missing build output should not go unnoticed.
+func matchAssets(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset pattern '%s': %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("asset pattern '%s' matches no files", pattern)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// repositoryPath returns the path of a repository from its URL, e.g. "group/subgroup/project"
+func repositoryPath(repoURL string) string {
+	parsed, err := url.Parse(changelog.NormalizeRemoteURL(repoURL))
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(parsed.Path, "/")
+}
@@ -0,0 +1,143 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// releasesPageSize is the number of releases requested per page when listing releases
+const releasesPageSize = 50
+
+// releaseResource is the part of a release resource herald uses. GitHub, Gitea and
+// Forgejo serve the same shape.
+type releaseResource struct {
+	ID        int64          `json:"id"`
+	TagName   string         `json:"tag_name"`
+	HTMLURL   string         `json:"html_url"`
+	UploadURL string         `json:"upload_url"` // Only served by GitHub
+	Assets    []releaseAsset `json:"assets"`
+}
+
+// releaseAsset is an uploaded release asset
+type releaseAsset struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// releaseRequest is the body of the create and update release requests
+type releaseRequest struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name"`
+	Body            string `json:"body"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+}
+
+// releaseAPI is a GitHub style releases API. The hosts differ in their endpoints,
+// authentication and the encoding of uploaded assets.
+type releaseAPI struct {
+	host           string            // Name used in messages, e.g. "GitHub"
+	headers        map[string]string // Authentication and content negotiation headers
+	listURL        func(page int) string
+	createURL      string
+	updateURL      func(release *releaseResource) string
+	deleteAssetURL func(release *releaseResource, asset releaseAsset) string
+	uploadURL      func(release *releaseResource, name string) string
+	encodeAsset    func(name string, data []byte) (contentType string, body []byte, err error)
+}
+
+// publish creates the release of the tag, or updates it when it already exists,
+// and uploads the assets, replacing assets with the same name. It returns the web URL of the release.
+func (r *releaseAPI) publish(ctx context.Context, release *Release, draft bool, assetPatterns []string) (string, error) {
+	api := newAPIClient(r.headers)
+
+	// Resolve the assets first, so a missing file does not leave a half published release
+	assets, err := matchAssets(assetPatterns)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := r.find(ctx, api, release.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	request := releaseRequest{
+		TagName:         release.Tag,
+		TargetCommitish: release.Commit,
+		Name:            release.Tag,
+		Body:            release.Notes,
+		Draft:           draft,
+		Prerelease:      release.Prerelease,
+	}
+
+	var published releaseResource
+	if existing == nil {
+		err = api.do(ctx, http.MethodPost, r.createURL, request, &published)
+	} else {
+		err = api.do(ctx, http.MethodPatch, r.updateURL(existing), request, &published)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to publish %s release %s: %w", r.host, release.Tag, err)
+	}
+
+	for _, asset := range assets {
+		if err := r.uploadAsset(ctx, api, &published, asset); err != nil {
+			return "", err
+		}
+	}
+
+	return published.HTMLURL, nil
+}
+
+// find returns the release of a tag, or nil when there is none. The releases are
+// listed because looking a release up by its tag leaves out drafts.
+func (r *releaseAPI) find(ctx context.Context, api *apiClient, tag string) (*releaseResource, error) {
+	for page := 1; ; page++ {
+		var releases []releaseResource
+		if err := api.do(ctx, http.MethodGet, r.listURL(page), nil, &releases); err != nil {
+			return nil, fmt.Errorf("failed to look up %s release %s: %w", r.host, tag, err)
+		}
+		for i := range releases {
+			if releases[i].TagName == tag {
+				return &releases[i], nil
+			}
+		}
+		if len(releases) < releasesPageSize {
+			return nil, nil
+		}
+	}
+}
+
+// uploadAsset uploads a file to a release, replacing an asset with the same name
+func (r *releaseAPI) uploadAsset(ctx context.Context, api *apiClient, release *releaseResource, path string) error {
+	name := filepath.Base(path)
+
+	for _, asset := range release.Assets {
+		if asset.Name != name {
+			continue
+		}
+		if err := api.do(ctx, http.MethodDelete, r.deleteAssetURL(release, asset), nil, nil); err != nil {
+			return fmt.Errorf("failed to replace asset %s: %w", name, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read asset %s: %w", path, err)
+	}
+
+	contentType, body, err := r.encodeAsset(name, data)
+	if err != nil {
+		return fmt.Errorf("failed to encode asset %s: %w", name, err)
+	}
+
+	if err := api.upload(ctx, http.MethodPost, r.uploadURL(release, name), contentType, body, nil); err != nil {
+		return fmt.Errorf("failed to upload asset %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"herald/internal/config"
+)
+
+// fakeRelease is a release as stored by fakeForge
+type fakeRelease struct {
+	ID              int64          `json:"id"`
+	TagName         string         `json:"tag_name"`
+	TargetCommitish string         `json:"target_commitish"`
+	Body            string         `json:"body"`
+	Draft           bool           `json:"draft"`
+	Prerelease      bool           `json:"prerelease"`
+	HTMLURL         string         `json:"html_url"`
+	UploadURL       string         `json:"upload_url,omitempty"`
+	Assets          []releaseAsset `json:"assets"`
+}
+
+// fakeForge serves the releases API of one repository, in the GitHub or the Gitea flavour
+type fakeForge struct {
+	t      *testing.T
+	gitea  bool
+	server *httptest.Server
+
+	mu       sync.Mutex
+	releases []*fakeRelease // Newest first, as the APIs list them
+	nextID   int64
+	requests []string // "METHOD path" of every request
+	auth     []string // Authorization header of every request
+	uploads  map[string][]byte
+}
+
+var (
+	releasesPath       = regexp.MustCompile(`^/repos/o/r/releases$`)
+	releasePath        = regexp.MustCompile(`^/repos/o/r/releases/(\d+)$`)
+	githubAssetPath    = regexp.MustCompile(`^/repos/o/r/releases/assets/(\d+)$`)
+	giteaAssetPath     = regexp.MustCompile(`^/repos/o/r/releases/(\d+)/assets/(\d+)$`)
+	githubUploadPath   = regexp.MustCompile(`^/uploads/releases/(\d+)/assets$`)
+	giteaAssetsPath    = regexp.MustCompile(`^/repos/o/r/releases/(\d+)/assets$`)
+	releasesPageParams = []string{"per_page", "limit"}
+)
+
+func newFakeForge(t *testing.T, gitea bool) *fakeForge {
+	forge := &fakeForge{t: t, gitea: gitea, nextID: 1, uploads: make(map[string][]byte)}
+	forge.server = httptest.NewServer(http.HandlerFunc(forge.serve))
+	t.Cleanup(forge.server.Close)
+	return forge
+}
+
+// add stores a release as if it had been published before
+func (f *fakeForge) add(tag string, draft bool, assets ...string) *fakeRelease {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	release := f.newRelease(tag)
+	release.Draft = draft
+	for _, name := range assets {
+		release.Assets = append(release.Assets, releaseAsset{ID: f.nextID, Name: name})
+		f.nextID++
+	}
+	f.releases = append([]*fakeRelease{release}, f.releases...)
+	return release
+}
+
+// newRelease creates a release with the next ID, the caller holds the lock
+func (f *fakeForge) newRelease(tag string) *fakeRelease {
+	id := f.nextID
+	f.nextID++
+	release := &fakeRelease{ID: id, TagName: tag, HTMLURL: fmt.Sprintf("https://forge.example.com/o/r/releases/%d", id), Assets: []releaseAsset{}}
+	if !f.gitea {
+		release.UploadURL = fmt.Sprintf("%s/uploads/releases/%d/assets{?name,label}", f.server.URL, id)
+	}
+	return release
+}
+
+func (f *fakeForge) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requestPath := r.URL.Path
+	if f.gitea {
+		requestPath = strings.TrimPrefix(requestPath, "/api/v1")
+	}
+	f.requests = append(f.requests, r.Method+" "+requestPath)
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodGet && releasesPath.MatchString(requestPath):
+		// Drafts are listed, unlike the lookup by tag
+		size := 30
+		for _, param := range releasesPageParams {
+			if value, err := strconv.Atoi(r.URL.Query().Get(param)); err == nil {
+				size = value
+			}
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start, end := min((page-1)*size, len(f.releases)), min(page*size, len(f.releases))
+		f.reply(w, http.StatusOK, f.releases[start:end])
+
+	case r.Method == http.MethodPost && releasesPath.MatchString(requestPath):
+		release := f.newRelease("")
+		f.decode(body, release)
+		f.releases = append([]*fakeRelease{release}, f.releases...)
+		f.reply(w, http.StatusCreated, release)
+
+	case r.Method == http.MethodPatch && releasePath.MatchString(requestPath):
+		release := f.release(releasePath.FindStringSubmatch(requestPath)[1])
+		if release == nil {
+			f.reply(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		f.decode(body, release)
+		f.reply(w, http.StatusOK, release)
+
+	case r.Method == http.MethodDelete && !f.gitea && githubAssetPath.MatchString(requestPath):
+		f.deleteAsset(githubAssetPath.FindStringSubmatch(requestPath)[1])
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodDelete && f.gitea && giteaAssetPath.MatchString(requestPath):
+		f.deleteAsset(giteaAssetPath.FindStringSubmatch(requestPath)[2])
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPost && (!f.gitea && githubUploadPath.MatchString(requestPath) || f.gitea && giteaAssetsPath.MatchString(requestPath)):
+		matches := githubUploadPath.FindStringSubmatch(requestPath)
+		if f.gitea {
+			matches = giteaAssetsPath.FindStringSubmatch(requestPath)
+		}
+		release := f.release(matches[1])
+		name := r.URL.Query().Get("name")
+		asset := releaseAsset{ID: f.nextID, Name: name}
+		f.nextID++
+		release.Assets = append(release.Assets, asset)
+		f.uploads[name] = body
+		f.reply(w, http.StatusCreated, asset)
+
+	default:
+		f.reply(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+func (f *fakeForge) decode(body []byte, release *fakeRelease) {
+	if err := json.Unmarshal(body, release); err != nil {
+		f.t.Errorf("invalid release request %s: %v", body, err)
+	}
+}
+
+func (f *fakeForge) reply(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func (f *fakeForge) release(id string) *fakeRelease {
+	for _, release := range f.releases {
+		if strconv.FormatInt(release.ID, 10) == id {
+			return release
+		}
+	}
+	return nil
+}
+
+func (f *fakeForge) deleteAsset(id string) {
+	for _, release := range f.releases {
+		for i, asset := range release.Assets {
+			if strconv.FormatInt(asset.ID, 10) == id {
+				release.Assets = append(release.Assets[:i], release.Assets[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// count returns how many requests were made with the method to paths matching the pattern
+func (f *fakeForge) count(method string, pattern *regexp.Regexp) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, request := range f.requests {
+		requestMethod, requestPath, _ := strings.Cut(request, " ")
+		if requestMethod == method && pattern.MatchString(requestPath) {
+			count++
+		}
+	}
+	return count
+}
+
+// forgeHost creates the publisher of a host for a fake forge
+type forgeHost struct {
+	name  string
+	gitea bool
+	auth  string
+	new   func(serverURL string, assets []string, draft bool) Publisher
+}
+
+var forgeHosts = []forgeHost{
+	{
+		name: "github",
+		auth: "Bearer secret",
+		new: func(serverURL string, assets []string, draft bool) Publisher {
+			cfg := config.DefaultConfig()
+			cfg.Publish.GitHub = config.GitHubPublishConfig{Enabled: true, APIURL: serverURL, Owner: "o", Repo: "r", TokenEnv: "HERALD_TEST_TOKEN", Assets: assets, Draft: draft}
+			return NewGitHub(cfg)
+		},
+	},
+	{
+		name:  "gitea",
+		gitea: true,
+		auth:  "token secret",
+		new: func(serverURL string, assets []string, draft bool) Publisher {
+			cfg := config.DefaultConfig()
+			cfg.Publish.Gitea = config.GiteaPublishConfig{Enabled: true, URL: serverURL, Owner: "o", Repo: "r", TokenEnv: "HERALD_TEST_TOKEN", Assets: assets, Draft: draft}
+			return NewGitea(cfg)
+		},
+	},
+}
+
+// testRelease is a release with notes, published from a commit
+func testRelease() *Release {
+	return &Release{
+		Project: "r",
+		Version: "v1.2.0",
+		Tag:     "v1.2.0",
+		Commit:  "abc1234def",
+		Notes:   "### Features\n\n* add export (abc1234)",
+	}
+}
+
+// writeAsset creates an asset file and returns its path
+func writeAsset(t *testing.T, name, content string) string {
+	t.Helper()
+	assetPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(assetPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return assetPath
+}
+
+func TestPublishCreatesRelease(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "secret")
+
+	for _, host := range forgeHosts {
+		t.Run(host.name, func(t *testing.T) {
+			forge := newFakeForge(t, host.gitea)
+			asset := writeAsset(t, "app.txt", "build output")
+
+			release := testRelease()
+			release.Prerelease = true
+			releaseURL, err := host.new(forge.server.URL, []string{asset}, true).Publish(context.Background(), release)
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			if len(forge.releases) != 1 {
+				t.Fatalf("got %d releases, want 1", len(forge.releases))
+			}
+			published := forge.releases[0]
+			if releaseURL != published.HTMLURL {
+				t.Errorf("Publish() = %q, want %q", releaseURL, published.HTMLURL)
+			}
+			if published.TagName != "v1.2.0" || published.TargetCommitish != "abc1234def" || published.Body != release.Notes {
+				t.Errorf("release = %+v", published)
+			}
+			if !published.Draft || !published.Prerelease {
+				t.Errorf("draft = %v, prerelease = %v, want both", published.Draft, published.Prerelease)
+			}
+			if len(published.Assets) != 1 || published.Assets[0].Name != "app.txt" {
+				t.Errorf("assets = %+v, want app.txt", published.Assets)
+			}
+			if !strings.Contains(string(forge.uploads["app.txt"]), "build output") {
+				t.Errorf("uploaded %q, want the file content", forge.uploads["app.txt"])
+			}
+			for i, auth := range forge.auth {
+				if auth != host.auth {
+					t.Errorf("request %s sent Authorization %q, want %q", forge.requests[i], auth, host.auth)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishUpdatesExistingRelease(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "secret")
+
+	for _, host := range forgeHosts {
+		t.Run(host.name, func(t *testing.T) {
+			forge := newFakeForge(t, host.gitea)
+			existing := forge.add("v1.2.0", false, "app.txt", "checksums.txt")
+			asset := writeAsset(t, "app.txt", "new build")
+
+			publisher := host.new(forge.server.URL, []string{asset}, false)
+			for i := 0; i < 2; i++ {
+				if _, err := publisher.Publish(context.Background(), testRelease()); err != nil {
+					t.Fatalf("Publish() #%d error = %v", i+1, err)
+				}
+			}
+
+			if len(forge.releases) != 1 {
+				t.Fatalf("got %d releases, want the existing one updated", len(forge.releases))
+			}
+			if got := forge.count(http.MethodPost, releasesPath); got != 0 {
+				t.Errorf("created %d releases, want none", got)
+			}
+			if got := forge.count(http.MethodPatch, releasePath); got != 2 {
+				t.Errorf("updated %d times, want 2", got)
+			}
+			if existing.Body != testRelease().Notes {
+				t.Errorf("body = %q, want the new notes", existing.Body)
+			}
+
+			var names []string
+			for _, asset := range existing.Assets {
+				names = append(names, asset.Name)
+			}
+			if strings.Join(names, ",") != "checksums.txt,app.txt" {
+				t.Errorf("assets = %v, want app.txt replaced and checksums.txt kept", names)
+			}
+		})
+	}
+}
+
+func TestPublishFindsDraftRelease(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "secret")
+
+	for _, host := range forgeHosts {
+		t.Run(host.name, func(t *testing.T) {
+			forge := newFakeForge(t, host.gitea)
+			draft := forge.add("v1.2.0", true)
+			// Push the draft to the second page of the listing
+			for i := 0; i < releasesPageSize; i++ {
+				forge.add(fmt.Sprintf("v0.%d.0", i), false)
+			}
+
+			if _, err := host.new(forge.server.URL, nil, false).Publish(context.Background(), testRelease()); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			if got := forge.count(http.MethodPost, releasesPath); got != 0 {
+				t.Errorf("created %d releases, want the draft updated", got)
+			}
+			if draft.Draft || draft.Body != testRelease().Notes {
+				t.Errorf("draft = %+v, want it published with the notes", draft)
+			}
+			if got := forge.count(http.MethodGet, releasesPath); got != 2 {
+				t.Errorf("listed %d pages, want 2", got)
+			}
+		})
+	}
+}
+
+func TestPublishWithoutToken(t *testing.T) {
+	t.Setenv("HERALD_TEST_TOKEN", "")
+
+	for _, host := range forgeHosts {
+		t.Run(host.name, func(t *testing.T) {
+			forge := newFakeForge(t, host.gitea)
+			_, err := host.new(forge.server.URL, nil, false).Publish(context.Background(), testRelease())
+			if err == nil || !strings.Contains(err.Error(), "$HERALD_TEST_TOKEN") {
+				t.Errorf("Publish() error = %v, want a missing token error", err)
+			}
+			if len(forge.requests) != 0 {
+				t.Errorf("sent %d requests, want none", len(forge.requests))
+			}
+		})
+	}
+}
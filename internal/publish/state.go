@@ -0,0 +1,114 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Result is the recorded outcome of one publisher
+type Result struct {
+	Publisher string    `json:"publisher"`
+	Succeeded bool      `json:"succeeded"`
+	URL       string    `json:"url,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// State records the publisher results of a tag, so a partially failed publish can be resumed
+type State struct {
+	Tag     string    `json:"tag"`
+	Results []*Result `json:"results"`
+
+	path string
+}
+
+// StatePath returns the file the results of a tag are recorded in
+func StatePath(gitDir, tag string) string {
+	return filepath.Join(gitDir, "herald", "publish", url.PathEscape(tag)+".json")
+}
+
+// NewState creates an empty state for a tag
+func NewState(path, tag string) *State {
+	return &State{Tag: tag, path: path}
+}
+
+// LoadState reads the recorded results, or returns nil when nothing was recorded
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read publish results: %w", err)
+	}
+
+	state := &State{path: path}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse publish results %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Succeeded reports whether a publisher already published the release
+func (s *State) Succeeded(publisher string) bool {
+	result := s.result(publisher)
+	return result != nil && result.Succeeded
+}
+
+// Failed returns the names of the publishers whose last attempt failed
+func (s *State) Failed() []string {
+	var failed []string
+	for _, result := range s.Results {
+		if !result.Succeeded {
+			failed = append(failed, result.Publisher)
+		}
+	}
+	return failed
+}
+
+// Record stores the outcome of a publisher, replacing its previous result
+func (s *State) Record(publisher, releaseURL string, err error) {
+	result := s.result(publisher)
+	if result == nil {
+		result = &Result{Publisher: publisher}
+		s.Results = append(s.Results, result)
+	}
+
+	result.Succeeded = err == nil
+	result.URL = releaseURL
+	result.Error = ""
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Time = time.Now().UTC()
+}
+
+// Save writes the results to the state file
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create publish results directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode publish results: %w", err)
+	}
+	if err := os.WriteFile(s.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write publish results: %w", err)
+	}
+	return nil
+}
+
+// result returns the recorded result of a publisher
+func (s *State) result(publisher string) *Result {
+	for _, result := range s.Results {
+		if result.Publisher == publisher {
+			return result
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package publish
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStatePath(t *testing.T) {
+	if got, want := StatePath("/repo/.git", "app/v1.2.0"), filepath.Join("/repo/.git", "herald", "publish", "app%2Fv1.2.0.json"); got != want {
+		t.Errorf("StatePath() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadStateWithoutResults(t *testing.T) {
+	state, err := LoadState(filepath.Join(t.TempDir(), "v1.2.0.json"))
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state != nil {
+		t.Errorf("LoadState() = %+v, want nil", state)
+	}
+}
+
+func TestStateRetry(t *testing.T) {
+	statePath := StatePath(t.TempDir(), "v1.2.0")
+
+	// First run: one publisher fails
+	state := NewState(statePath, "v1.2.0")
+	state.Record("github", "https://github.com/o/r/releases/tag/v1.2.0", nil)
+	state.Record("notify", "", errors.New("slack: 500 Internal Server Error"))
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Retry: only the failed publisher runs again
+	state, err := LoadState(statePath)
+	if err != nil || state == nil {
+		t.Fatalf("LoadState() = %v, %v", state, err)
+	}
+	if state.Tag != "v1.2.0" {
+		t.Errorf("Tag = %q, want v1.2.0", state.Tag)
+	}
+	if !state.Succeeded("github") || state.Succeeded("notify") || state.Succeeded("gitlab") {
+		t.Errorf("Succeeded() github, notify, gitlab = %v, %v, %v, want true, false, false",
+			state.Succeeded("github"), state.Succeeded("notify"), state.Succeeded("gitlab"))
+	}
+	if failed := state.Failed(); !slices.Equal(failed, []string{"notify"}) {
+		t.Errorf("Failed() = %v, want [notify]", failed)
+	}
+	if result := state.result("notify"); result.Error != "slack: 500 Internal Server Error" {
+		t.Errorf("notify error = %q, want the recorded error", result.Error)
+	}
+
+	state.Record("notify", "", nil)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	state, err = LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if len(state.Results) != 2 {
+		t.Errorf("got %d results, want the notify result replaced", len(state.Results))
+	}
+	if failed := state.Failed(); len(failed) != 0 {
+		t.Errorf("Failed() = %v, want none", failed)
+	}
+	if result := state.result("notify"); !result.Succeeded || result.Error != "" {
+		t.Errorf("notify result = %+v, want succeeded without an error", result)
+	}
+	if result := state.result("github"); result.URL != "https://github.com/o/r/releases/tag/v1.2.0" {
+		t.Errorf("github URL = %q, want the first run's URL", result.URL)
+	}
+}
@@ -174,6 +174,19 @@ func (v *Version) IsGreaterThan(other *Version) bool {
 	return v.Compare(other) > 0
 }
 
+// BumpBetween returns the bump that leads from one version to a greater one.
+// A change of the prerelease only counts as a patch.
+func BumpBetween(from, to *Version) commits.BumpType {
+	switch {
+	case to.Major != from.Major:
+		return commits.Major
+	case to.Minor != from.Minor:
+		return commits.Minor
+	default:
+		return commits.Patch
+	}
+}
+
 // GetCurrentVersion gets the current version from the latest git tag
 func (m *Manager) GetCurrentVersion(latestTag string) (*Version, error) {
 	if latestTag == "" {